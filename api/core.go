@@ -436,6 +436,14 @@ type (
 		ConsumedOutputs []*OutputWithID `serix:",lenPrefix=uint32"`
 	}
 
+	// CommitmentInfoResponse defines the payload of the latest and finalized commitment info event API topics.
+	CommitmentInfoResponse struct {
+		// CommitmentID is the ID of the commitment.
+		CommitmentID iotago.CommitmentID `serix:""`
+		// CommitmentSlot is the slot of the commitment.
+		CommitmentSlot iotago.SlotIndex `serix:""`
+	}
+
 	// CongestionResponse defines the response for the congestion REST API call.
 	CongestionResponse struct {
 		// Slot is the slot for which the estimate is provided.
@@ -199,6 +199,16 @@ func Test_CoreAPIDeSerialize(t *testing.T) {
 			SeriErr:   nil,
 			DeSeriErr: nil,
 		},
+		{
+			Name: "ok - CommitmentInfoResponse",
+			Source: &api.CommitmentInfoResponse{
+				CommitmentID:   tpkg.RandCommitmentID(),
+				CommitmentSlot: tpkg.RandSlot(),
+			},
+			Target:    &api.CommitmentInfoResponse{},
+			SeriErr:   nil,
+			DeSeriErr: nil,
+		},
 		{
 			Name: "ok - CongestionResponse",
 			Source: &api.CongestionResponse{
@@ -688,6 +698,17 @@ func Test_CoreAPIJSONSerialization(t *testing.T) {
 			}
 		}
 	]
+}`,
+		},
+		{
+			Name: "ok - CommitmentInfoResponse",
+			Source: &api.CommitmentInfoResponse{
+				CommitmentID:   iotago.NewCommitmentID(42, iotago.Identifier{}),
+				CommitmentSlot: 42,
+			},
+			Target: `{
+	"commitmentId": "0x00000000000000000000000000000000000000000000000000000000000000002a000000",
+	"commitmentSlot": 42
 }`,
 		},
 		{
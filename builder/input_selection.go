@@ -0,0 +1,366 @@
+package builder
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/iotaledger/hive.go/core/safemath"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+)
+
+// ErrInsufficientInputs gets returned if the candidate inputs do not cover the requirements of the transaction.
+var ErrInsufficientInputs = ierrors.New("insufficient inputs")
+
+// InputCandidate is a candidate input for the input selection.
+type InputCandidate struct {
+	*TxInput
+	// The mana (potential and decayed stored mana) the input holds at the target slot.
+	Mana iotago.Mana
+}
+
+// InputSelectionStrategy returns the given candidates in the order in which they should be selected.
+// The base tokens which are still missing to cover the outputs are passed as a hint.
+type InputSelectionStrategy func(candidates []*InputCandidate, requiredBaseTokens iotago.BaseToken) []*InputCandidate
+
+// InputSelectionStrategyLargestFirst selects the candidates with the highest amount of base tokens first.
+func InputSelectionStrategyLargestFirst(candidates []*InputCandidate, _ iotago.BaseToken) []*InputCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Input.BaseTokenAmount() > candidates[j].Input.BaseTokenAmount()
+	})
+
+	return candidates
+}
+
+// InputSelectionStrategyFewestInputs selects the smallest candidate that covers the required base tokens on its own.
+// If no such candidate exists, the candidates with the highest amount of base tokens are selected first.
+func InputSelectionStrategyFewestInputs(candidates []*InputCandidate, requiredBaseTokens iotago.BaseToken) []*InputCandidate {
+	candidates = InputSelectionStrategyLargestFirst(candidates, requiredBaseTokens)
+
+	// the candidates are sorted in descending order, so the last one which covers the required amount is the smallest one
+	bestFit := -1
+	for i, candidate := range candidates {
+		if candidate.Input.BaseTokenAmount() < requiredBaseTokens {
+			break
+		}
+		bestFit = i
+	}
+
+	if bestFit > 0 {
+		candidates[0], candidates[bestFit] = candidates[bestFit], candidates[0]
+	}
+
+	return candidates
+}
+
+// InputSelectionStrategyDustConsolidation selects the candidates with the lowest amount of base tokens first,
+// so that small outputs get consolidated over time.
+func InputSelectionStrategyDustConsolidation(candidates []*InputCandidate, _ iotago.BaseToken) []*InputCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Input.BaseTokenAmount() < candidates[j].Input.BaseTokenAmount()
+	})
+
+	return candidates
+}
+
+// InputSelectionOptions defines the options for the input selection.
+type InputSelectionOptions struct {
+	strategy             InputSelectionStrategy
+	filter               TransactionBuilderInputFilter
+	maxInputs            int
+	blockIssuerAccountID iotago.AccountID
+	rmc                  iotago.Mana
+	allotRequiredMana    bool
+	remainderAddress     iotago.Address
+}
+
+// WithInputSelectionStrategy sets the strategy which defines the order in which candidates are selected.
+func WithInputSelectionStrategy(strategy InputSelectionStrategy) options.Option[InputSelectionOptions] {
+	return func(o *InputSelectionOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithInputSelectionFilter sets a filter which determines whether a candidate may be selected.
+func WithInputSelectionFilter(filter TransactionBuilderInputFilter) options.Option[InputSelectionOptions] {
+	return func(o *InputSelectionOptions) {
+		o.filter = filter
+	}
+}
+
+// WithInputSelectionMaxInputs sets the maximum amount of inputs of the transaction.
+func WithInputSelectionMaxInputs(maxInputs int) options.Option[InputSelectionOptions] {
+	return func(o *InputSelectionOptions) {
+		o.maxInputs = maxInputs
+	}
+}
+
+// WithInputSelectionRequiredAllotment makes the input selection also cover the minimum mana
+// that needs to be alloted to the block issuer account to issue the transaction.
+func WithInputSelectionRequiredAllotment(rmc iotago.Mana, blockIssuerAccountID iotago.AccountID) options.Option[InputSelectionOptions] {
+	return func(o *InputSelectionOptions) {
+		o.allotRequiredMana = true
+		o.rmc = rmc
+		o.blockIssuerAccountID = blockIssuerAccountID
+	}
+}
+
+// WithInputSelectionRemainderAddress sets the address of the remainder outputs, whose storage deposit
+// is covered by the selected inputs. Defaults to an Ed25519Address.
+func WithInputSelectionRemainderAddress(remainderAddress iotago.Address) options.Option[InputSelectionOptions] {
+	return func(o *InputSelectionOptions) {
+		o.remainderAddress = remainderAddress
+	}
+}
+
+// SelectInputs selects inputs from the given candidates until the base tokens, native tokens and mana
+// required by the outputs and allotments of the builder are covered, and adds them to the builder.
+// Inputs which were already added to the builder are taken into account.
+// If the selected inputs exceed the requirements, they also cover the storage deposit of the remainder outputs
+// which are needed to hold the leftover, see AddRemainderOutputs.
+//
+// Only BasicOutputs can be selected automatically, since chain outputs need a transition on the output side.
+// Candidates which can't be unlocked by their UnlockTarget because of timelock or expiration unlock conditions,
+// checked like the VM does for a commitment input of the target slot, as well as candidates with
+// storage deposit return unlock conditions, are skipped.
+func (b *TransactionBuilder) SelectInputs(targetSlot iotago.SlotIndex, candidates []*TxInput, opts ...options.Option[InputSelectionOptions]) *TransactionBuilder {
+	setBuildError := func(err error) *TransactionBuilder {
+		b.occurredBuildErr = err
+		return b
+	}
+
+	if b.occurredBuildErr != nil {
+		return b
+	}
+
+	selectionOpts := options.Apply(&InputSelectionOptions{
+		strategy:         InputSelectionStrategyLargestFirst,
+		maxInputs:        iotago.MaxInputsCount,
+		remainderAddress: &iotago.Ed25519Address{},
+	}, opts)
+
+	remainingCandidates := make([]*InputCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !b.isSelectable(targetSlot, candidate, selectionOpts.filter) {
+			continue
+		}
+
+		mana, err := b.inputMana(targetSlot, candidate.InputID, candidate.Input)
+		if err != nil {
+			return setBuildError(err)
+		}
+
+		remainingCandidates = append(remainingCandidates, &InputCandidate{TxInput: candidate, Mana: mana})
+	}
+
+	for {
+		missing, err := b.missingBalances(targetSlot, selectionOpts)
+		if err != nil {
+			return setBuildError(err)
+		}
+
+		if missing.covered() {
+			return b
+		}
+
+		if len(b.transaction.TransactionEssence.Inputs) >= selectionOpts.maxInputs {
+			return setBuildError(ierrors.Wrapf(ErrInsufficientInputs, "maximum amount of inputs %d reached, missing %s", selectionOpts.maxInputs, missing))
+		}
+
+		remainingCandidates = selectionOpts.strategy(remainingCandidates, missing.baseTokens)
+
+		selectedIndex := -1
+		for i, candidate := range remainingCandidates {
+			if missing.coveredBy(candidate) {
+				selectedIndex = i
+				break
+			}
+		}
+
+		if selectedIndex == -1 {
+			return setBuildError(ierrors.Wrapf(ErrInsufficientInputs, "missing %s", missing))
+		}
+
+		b.AddInput(remainingCandidates[selectedIndex].TxInput)
+		remainingCandidates = append(remainingCandidates[:selectedIndex], remainingCandidates[selectedIndex+1:]...)
+	}
+}
+
+// isSelectable checks whether the given candidate can be selected as an input at the target slot.
+func (b *TransactionBuilder) isSelectable(targetSlot iotago.SlotIndex, candidate *TxInput, filter TransactionBuilderInputFilter) bool {
+	if _, alreadyAdded := b.inputs[candidate.InputID]; alreadyAdded {
+		return false
+	}
+
+	basicOutput, isBasicOutput := candidate.Input.(*iotago.BasicOutput)
+	if !isBasicOutput {
+		return false
+	}
+
+	// basic outputs owned by an implicit account creation address are implicit accounts
+	if basicOutput.Ident().Type() == iotago.AddressImplicitAccountCreation {
+		return false
+	}
+
+	if basicOutput.UnlockConditionSet().HasStorageDepositReturnCondition() {
+		return false
+	}
+
+	// the VM checks the unlock conditions against the slots bounded by the committable age of the commitment input
+	protocolParameters := b.api.ProtocolParameters()
+	if !basicOutput.UnlockableBy(candidate.UnlockTarget, targetSlot+protocolParameters.MaxCommittableAge(), targetSlot+protocolParameters.MinCommittableAge()) {
+		return false
+	}
+
+	if filter != nil && !filter(candidate.InputID, candidate.Input) {
+		return false
+	}
+
+	return true
+}
+
+// inputMana returns the potential and decayed stored mana of the given input at the target slot.
+func (b *TransactionBuilder) inputMana(targetSlot iotago.SlotIndex, inputID iotago.OutputID, input iotago.Output) (iotago.Mana, error) {
+	potentialMana, err := iotago.PotentialMana(b.api.ManaDecayProvider(), b.api.StorageScoreStructure(), input, inputID.CreationSlot(), targetSlot)
+	if err != nil {
+		return 0, ierrors.Wrap(err, "failed to calculate potential mana")
+	}
+
+	storedMana, err := b.api.ManaDecayProvider().DecayManaBySlots(input.StoredMana(), inputID.CreationSlot(), targetSlot)
+	if err != nil {
+		return 0, ierrors.Wrap(err, "failed to calculate stored mana decay")
+	}
+
+	return safemath.SafeAdd(potentialMana, storedMana)
+}
+
+// missingBalances contains the balances that are not yet covered by the inputs of the transaction.
+type missingBalances struct {
+	baseTokens   iotago.BaseToken
+	mana         iotago.Mana
+	nativeTokens iotago.NativeTokenSum
+}
+
+func (m *missingBalances) covered() bool {
+	return m.baseTokens == 0 && m.mana == 0 && len(m.nativeTokens) == 0
+}
+
+// coveredBy checks whether the given candidate reduces any of the missing balances.
+func (m *missingBalances) coveredBy(candidate *InputCandidate) bool {
+	if m.baseTokens > 0 && candidate.Input.BaseTokenAmount() > 0 {
+		return true
+	}
+
+	if m.mana > 0 && candidate.Mana > 0 {
+		return true
+	}
+
+	if nativeToken := candidate.Input.FeatureSet().NativeToken(); nativeToken != nil {
+		if _, isMissing := m.nativeTokens[nativeToken.ID]; isMissing {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *missingBalances) String() string {
+	return fmt.Sprintf("base tokens: %d, mana: %d, native tokens: %d", m.baseTokens, m.mana, len(m.nativeTokens))
+}
+
+// missingBalances calculates the balances which are required by the outputs and allotments of the transaction
+// but are not yet covered by its inputs.
+func (b *TransactionBuilder) missingBalances(targetSlot iotago.SlotIndex, selectionOpts *InputSelectionOptions) (*missingBalances, error) {
+	var err error
+
+	var requiredBaseTokens iotago.BaseToken
+	var requiredMana iotago.Mana
+	requiredNativeTokens := make(iotago.NativeTokenSum)
+
+	for _, output := range b.transaction.Outputs {
+		if requiredBaseTokens, err = safemath.SafeAdd(requiredBaseTokens, output.BaseTokenAmount()); err != nil {
+			return nil, ierrors.Wrap(err, "failed to sum the base tokens of the outputs")
+		}
+
+		if requiredMana, err = safemath.SafeAdd(requiredMana, output.StoredMana()); err != nil {
+			return nil, ierrors.Wrap(err, "failed to sum the stored mana of the outputs")
+		}
+
+		if nativeToken := output.FeatureSet().NativeToken(); nativeToken != nil {
+			requiredNativeTokens[nativeToken.ID] = new(big.Int).Add(requiredNativeTokens.ValueOrBigInt0(nativeToken.ID), nativeToken.Amount)
+		}
+	}
+
+	for _, allotment := range b.transaction.Allotments {
+		if requiredMana, err = safemath.SafeAdd(requiredMana, allotment.Mana); err != nil {
+			return nil, ierrors.Wrap(err, "failed to sum the alloted mana")
+		}
+	}
+
+	if selectionOpts.allotRequiredMana {
+		minRequiredMana, err := b.MinRequiredAllotedMana(b.api.ProtocolParameters().WorkScoreParameters(), selectionOpts.rmc, selectionOpts.blockIssuerAccountID)
+		if err != nil {
+			return nil, ierrors.Wrap(err, "failed to calculate the minimum required mana to issue the block")
+		}
+
+		if requiredMana, err = safemath.SafeAdd(requiredMana, minRequiredMana); err != nil {
+			return nil, ierrors.Wrap(err, "failed to add the minimum required mana to issue the block")
+		}
+	}
+
+	var availableBaseTokens iotago.BaseToken
+	for _, input := range b.inputs {
+		if availableBaseTokens, err = safemath.SafeAdd(availableBaseTokens, input.BaseTokenAmount()); err != nil {
+			return nil, ierrors.Wrap(err, "failed to sum the base tokens of the inputs")
+		}
+
+		if nativeToken := input.FeatureSet().NativeToken(); nativeToken != nil {
+			if required, isRequired := requiredNativeTokens[nativeToken.ID]; isRequired {
+				required.Sub(required, nativeToken.Amount)
+				if required.Sign() <= 0 {
+					delete(requiredNativeTokens, nativeToken.ID)
+				}
+			}
+		}
+	}
+
+	availableMana, err := b.CalculateAvailableMana(targetSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := &missingBalances{nativeTokens: requiredNativeTokens}
+	if requiredBaseTokens > availableBaseTokens {
+		missing.baseTokens = requiredBaseTokens - availableBaseTokens
+	}
+	if requiredMana > availableMana.TotalMana {
+		missing.mana = requiredMana - availableMana.TotalMana
+	}
+
+	if !missing.covered() {
+		return missing, nil
+	}
+
+	// the leftover of the inputs needs remainder outputs, whose storage deposit must be covered as well
+	leftoverBaseTokens, leftoverNativeTokens, err := b.leftoverBaseTokensAndNativeTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	if leftoverBaseTokens == 0 && availableMana.TotalMana == requiredMana && len(leftoverNativeTokens) == 0 {
+		return missing, nil
+	}
+
+	_, requiredStorageDeposit, err := b.remainderOutputs(selectionOpts.remainderAddress, leftoverNativeTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	if requiredStorageDeposit > leftoverBaseTokens {
+		missing.baseTokens = requiredStorageDeposit - leftoverBaseTokens
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,224 @@
+//nolint:scopelint
+package builder_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/lo"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/builder"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+func TestTransactionBuilderSelectInputs(t *testing.T) {
+	ownerAddr := tpkg.RandEd25519Address()
+	nativeTokenID := tpkg.RandNativeTokenID()
+
+	candidate := func(amount iotago.BaseToken, unlockConditions ...iotago.BasicOutputUnlockCondition) *builder.TxInput {
+		return &builder.TxInput{
+			UnlockTarget: ownerAddr,
+			InputID:      tpkg.RandOutputID(0),
+			Input: &iotago.BasicOutput{
+				Amount:           amount,
+				UnlockConditions: append(iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: ownerAddr}}, unlockConditions...),
+			},
+		}
+	}
+
+	nativeTokenCandidate := func(amount iotago.BaseToken, nativeTokenAmount int64) *builder.TxInput {
+		input := candidate(amount)
+		//nolint:forcetypeassert // we can safely assume that this is a BasicOutput
+		input.Input.(*iotago.BasicOutput).Features = iotago.BasicOutputFeatures{
+			&iotago.NativeTokenFeature{ID: nativeTokenID, Amount: big.NewInt(nativeTokenAmount)},
+		}
+
+		return input
+	}
+
+	output := func(amount iotago.BaseToken, features ...iotago.BasicOutputFeature) *iotago.BasicOutput {
+		return &iotago.BasicOutput{
+			Amount:           amount,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: tpkg.RandEd25519Address()}},
+			Features:         features,
+		}
+	}
+
+	small, medium, large := candidate(100), candidate(500), candidate(1000)
+	timelocked := candidate(5000, &iotago.TimelockUnlockCondition{Slot: 100})
+	expired := candidate(5000, &iotago.ExpirationUnlockCondition{ReturnAddress: tpkg.RandEd25519Address(), Slot: 5})
+	withNativeTokens := nativeTokenCandidate(50, 10)
+
+	type test struct {
+		name           string
+		outputs        []iotago.Output
+		candidates     []*builder.TxInput
+		opts           []options.Option[builder.InputSelectionOptions]
+		expectedInputs []*builder.TxInput
+		wantErr        error
+	}
+
+	tests := []*test{
+		{
+			name:           "ok - largest first",
+			outputs:        []iotago.Output{output(600)},
+			candidates:     []*builder.TxInput{small, medium, large},
+			expectedInputs: []*builder.TxInput{large},
+		},
+		{
+			name:           "ok - largest first with several inputs",
+			outputs:        []iotago.Output{output(1200)},
+			candidates:     []*builder.TxInput{small, medium, large},
+			expectedInputs: []*builder.TxInput{large, medium},
+		},
+		{
+			name:           "ok - fewest inputs",
+			outputs:        []iotago.Output{output(400)},
+			candidates:     []*builder.TxInput{small, medium, large},
+			opts:           []options.Option[builder.InputSelectionOptions]{builder.WithInputSelectionStrategy(builder.InputSelectionStrategyFewestInputs)},
+			expectedInputs: []*builder.TxInput{medium},
+		},
+		{
+			name:           "ok - dust consolidation",
+			outputs:        []iotago.Output{output(550)},
+			candidates:     []*builder.TxInput{small, medium, large},
+			opts:           []options.Option[builder.InputSelectionOptions]{builder.WithInputSelectionStrategy(builder.InputSelectionStrategyDustConsolidation)},
+			expectedInputs: []*builder.TxInput{small, medium},
+		},
+		{
+			name:           "ok - native tokens",
+			outputs:        []iotago.Output{output(50, &iotago.NativeTokenFeature{ID: nativeTokenID, Amount: big.NewInt(10)}), output(500)},
+			candidates:     []*builder.TxInput{small, large, withNativeTokens},
+			expectedInputs: []*builder.TxInput{large, withNativeTokens},
+		},
+		{
+			name:           "ok - filter",
+			outputs:        []iotago.Output{output(400)},
+			candidates:     []*builder.TxInput{small, medium, large},
+			opts:           []options.Option[builder.InputSelectionOptions]{builder.WithInputSelectionFilter(func(outputID iotago.OutputID, _ iotago.Output) bool { return outputID != large.InputID })},
+			expectedInputs: []*builder.TxInput{medium},
+		},
+		{
+			name:       "err - timelocked and expired candidates are skipped",
+			outputs:    []iotago.Output{output(2000)},
+			candidates: []*builder.TxInput{large, timelocked, expired},
+			wantErr:    builder.ErrInsufficientInputs,
+		},
+		{
+			name:       "err - max inputs",
+			outputs:    []iotago.Output{output(1200)},
+			candidates: []*builder.TxInput{small, medium, large},
+			opts:       []options.Option[builder.InputSelectionOptions]{builder.WithInputSelectionMaxInputs(1)},
+			wantErr:    builder.ErrInsufficientInputs,
+		},
+		{
+			name:       "err - native tokens missing",
+			outputs:    []iotago.Output{output(50, &iotago.NativeTokenFeature{ID: nativeTokenID, Amount: big.NewInt(20)})},
+			candidates: []*builder.TxInput{large, withNativeTokens},
+			wantErr:    builder.ErrInsufficientInputs,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bdl := builder.NewTransactionBuilder(tpkg.ZeroCostTestAPI).SetCreationSlot(10)
+			for _, output := range test.outputs {
+				bdl.AddOutput(output)
+			}
+
+			bdl.SelectInputs(10, test.candidates, test.opts...)
+
+			tx, err := bdl.Build(&iotago.EmptyAddressSigner{})
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+
+			inputs, err := tx.Transaction.Inputs()
+			require.NoError(t, err)
+			require.Equal(t, lo.Map(test.expectedInputs, func(input *builder.TxInput) iotago.OutputID {
+				return input.InputID
+			}), lo.Map(inputs, func(input *iotago.UTXOInput) iotago.OutputID {
+				return input.OutputID()
+			}))
+		})
+	}
+}
+
+func TestTransactionBuilderSelectInputsCommittableAge(t *testing.T) {
+	const targetSlot iotago.SlotIndex = 100
+
+	ownerAddr := tpkg.RandEd25519Address()
+	protocolParameters := tpkg.ZeroCostTestAPI.ProtocolParameters()
+
+	candidate := func(unlockCondition iotago.BasicOutputUnlockCondition) *builder.TxInput {
+		return &builder.TxInput{
+			UnlockTarget: ownerAddr,
+			InputID:      tpkg.RandOutputID(0),
+			Input: &iotago.BasicOutput{
+				Amount:           1000,
+				UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: ownerAddr}, unlockCondition},
+			},
+		}
+	}
+
+	// the VM checks the timelock against the commitment slot plus the min committable age
+	timelockExpiring := candidate(&iotago.TimelockUnlockCondition{Slot: targetSlot + protocolParameters.MinCommittableAge()})
+	// and the expiration against the commitment slot plus the max committable age
+	expiring := candidate(&iotago.ExpirationUnlockCondition{ReturnAddress: tpkg.RandEd25519Address(), Slot: targetSlot + protocolParameters.MaxCommittableAge()})
+
+	selectInputs := func(candidates ...*builder.TxInput) (*iotago.SignedTransaction, error) {
+		return builder.NewTransactionBuilder(tpkg.ZeroCostTestAPI).
+			SetCreationSlot(targetSlot).
+			AddOutput(&iotago.BasicOutput{
+				Amount:           1000,
+				UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: tpkg.RandEd25519Address()}},
+			}).
+			SelectInputs(targetSlot, candidates).
+			Build(&iotago.EmptyAddressSigner{})
+	}
+
+	_, err := selectInputs(timelockExpiring)
+	require.NoError(t, err)
+
+	_, err = selectInputs(expiring)
+	require.ErrorIs(t, err, builder.ErrInsufficientInputs)
+}
+
+func TestTransactionBuilderSelectInputsRemainderStorageDeposit(t *testing.T) {
+	testAPI := iotago.V3API(tpkg.IOTAMainnetV3TestProtocolParameters)
+
+	ownerAddr := tpkg.RandEd25519Address()
+	remainderAddr := tpkg.RandEd25519Address()
+
+	basicOutput := func(addr iotago.Address, amount iotago.BaseToken) *iotago.BasicOutput {
+		return &iotago.BasicOutput{
+			Amount:           amount,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: addr}},
+		}
+	}
+
+	remainderDeposit, err := testAPI.StorageScoreStructure().MinDeposit(basicOutput(remainderAddr, 0))
+	require.NoError(t, err)
+
+	// covers the output, but the leftover doesn't cover the storage deposit of the remainder output
+	almostExact := &builder.TxInput{UnlockTarget: ownerAddr, InputID: tpkg.RandOutputID(0), Input: basicOutput(ownerAddr, 1_000_000+remainderDeposit/2)}
+	small := &builder.TxInput{UnlockTarget: ownerAddr, InputID: tpkg.RandOutputID(0), Input: basicOutput(ownerAddr, 2*remainderDeposit)}
+
+	tx, err := builder.NewTransactionBuilder(testAPI).
+		AddOutput(basicOutput(tpkg.RandEd25519Address(), 1_000_000)).
+		SelectInputs(0, []*builder.TxInput{almostExact, small}, builder.WithInputSelectionRemainderAddress(remainderAddr)).
+		AddRemainderOutputs(0, remainderAddr, builder.WithRemainderDustMerging(false)).
+		Build(&iotago.EmptyAddressSigner{})
+	require.NoError(t, err)
+
+	inputs, err := tx.Transaction.Inputs()
+	require.NoError(t, err)
+	require.Len(t, inputs, 2)
+	require.Len(t, tx.Transaction.Outputs, 2)
+}
@@ -0,0 +1,274 @@
+package builder
+
+import (
+	"context"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	iotago "github.com/iotaledger/iota.go/v4"
+)
+
+var (
+	// ErrPartiallySignedTransactionMismatch gets returned if partially signed transactions of different transactions are combined.
+	ErrPartiallySignedTransactionMismatch = ierrors.New("partially signed transactions do not belong to the same transaction")
+	// ErrPartiallySignedTransactionInputsInvalid gets returned if the inputs of a partially signed transaction do not match the transaction.
+	ErrPartiallySignedTransactionInputsInvalid = ierrors.New("partially signed transaction inputs do not match the transaction inputs")
+)
+
+// PartiallySignedTransactionInput defines an input of a PartiallySignedTransaction with the address to unlock.
+type PartiallySignedTransactionInput struct {
+	// The address which needs to be unlocked to spend this input.
+	UnlockTarget iotago.Address `serix:""`
+	// The ID of the referenced input.
+	InputID iotago.OutputID `serix:""`
+	// The output which is used as an input.
+	Input iotago.TxEssenceOutput `serix:""`
+}
+
+// PartiallySignedTransaction holds a Transaction together with its resolved inputs and the signatures collected so far.
+// It can be passed between an online machine building the transaction, an air-gapped machine holding the keys
+// or several co-signers of a MultiAddress, without the need to share any keys.
+type PartiallySignedTransaction struct {
+	API iotago.API
+	// The transaction which needs to be signed.
+	Transaction *iotago.Transaction `serix:""`
+	// The resolved inputs in the same order as the inputs of the transaction.
+	Inputs []*PartiallySignedTransactionInput `serix:",lenPrefix=uint16"`
+	// The signatures over the signing message of the transaction collected so far.
+	Signatures []iotago.Signature `serix:",lenPrefix=uint16"`
+}
+
+// BuildPartiallySigned returns a PartiallySignedTransaction of the transaction without any signatures.
+func (b *TransactionBuilder) BuildPartiallySigned() (*PartiallySignedTransaction, error) {
+	if b.occurredBuildErr != nil {
+		return nil, b.occurredBuildErr
+	}
+
+	b.transaction.Allotments.Sort()
+	b.transaction.TransactionEssence.ContextInputs.Sort()
+
+	inputs := make([]*PartiallySignedTransactionInput, 0, len(b.transaction.TransactionEssence.Inputs))
+	for _, input := range b.transaction.TransactionEssence.Inputs {
+		//nolint:forcetypeassert // we can safely assume that this is an UTXOInput
+		inputID := input.(*iotago.UTXOInput).OutputID()
+
+		inputs = append(inputs, &PartiallySignedTransactionInput{
+			UnlockTarget: b.inputOwner[inputID],
+			InputID:      inputID,
+			Input:        b.inputs[inputID],
+		})
+	}
+
+	return &PartiallySignedTransaction{
+		API:         b.api,
+		Transaction: b.transaction.Clone(),
+		Inputs:      inputs,
+		Signatures:  []iotago.Signature{},
+	}, nil
+}
+
+// PartiallySignedTransactionFromBytes returns a function that decodes a PartiallySignedTransaction from bytes using the given API.
+func PartiallySignedTransactionFromBytes(api iotago.API) func([]byte) (*PartiallySignedTransaction, int, error) {
+	return func(b []byte) (tx *PartiallySignedTransaction, consumedBytes int, err error) {
+		tx = new(PartiallySignedTransaction)
+		consumedBytes, err = api.Decode(b, tx)
+
+		return tx, consumedBytes, err
+	}
+}
+
+// PartiallySignedTransactionFromJSON decodes a PartiallySignedTransaction from JSON using the given API.
+func PartiallySignedTransactionFromJSON(api iotago.API, jsonBytes []byte) (*PartiallySignedTransaction, error) {
+	tx := new(PartiallySignedTransaction)
+	if err := api.JSONDecode(jsonBytes, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Bytes returns the binary encoding of the PartiallySignedTransaction.
+func (p *PartiallySignedTransaction) Bytes() ([]byte, error) {
+	return p.API.Encode(p)
+}
+
+// JSONEncode returns the JSON encoding of the PartiallySignedTransaction.
+func (p *PartiallySignedTransaction) JSONEncode() ([]byte, error) {
+	return p.API.JSONEncode(p)
+}
+
+// SetDeserializationContext sets the API of the PartiallySignedTransaction from the deserialization context.
+func (p *PartiallySignedTransaction) SetDeserializationContext(ctx context.Context) {
+	p.API = iotago.APIFromContext(ctx)
+}
+
+// Sign adds the signatures of all addresses which need to be unlocked and are known to the given signer.
+// Addresses the signer has no keys for are skipped.
+func (p *PartiallySignedTransaction) Sign(signer iotago.AddressSigner) error {
+	txEssenceData, err := p.Transaction.SigningMessage()
+	if err != nil {
+		return ierrors.Wrap(err, "failed to calculate tx transaction for signing message")
+	}
+
+	signed := make(map[string]struct{})
+	sign := func(addr iotago.Address) error {
+		if _, has := signed[addr.Key()]; has {
+			return nil
+		}
+		signed[addr.Key()] = struct{}{}
+
+		signature, err := signer.Sign(addr, txEssenceData)
+		if err != nil {
+			if ierrors.Is(err, iotago.ErrAddressKeysNotMapped) {
+				return nil
+			}
+
+			return ierrors.Wrapf(err, "failed to sign tx transaction for address %s", addr)
+		}
+
+		return p.AddSignatures(signature)
+	}
+
+	for i, input := range p.Inputs {
+		switch addr := resolveUnderlyingAddress(input.UnlockTarget).(type) {
+		case iotago.ChainAddress:
+			// chain addresses are unlocked by reference
+			continue
+
+		case *iotago.MultiAddress:
+			for _, member := range addr.Addresses {
+				if _, isChainAddress := member.Address.(iotago.ChainAddress); isChainAddress {
+					continue
+				}
+
+				if err := sign(member.Address); err != nil {
+					return ierrors.Wrapf(err, "failed to sign input %d's multi address", i)
+				}
+			}
+
+		default:
+			if err := sign(input.UnlockTarget); err != nil {
+				return ierrors.Wrapf(err, "failed to sign input %d", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddSignatures adds the given signatures after verifying them against the signing message of the transaction.
+// Signatures which were already added before are ignored.
+func (p *PartiallySignedTransaction) AddSignatures(signatures ...iotago.Signature) error {
+	txEssenceData, err := p.Transaction.SigningMessage()
+	if err != nil {
+		return ierrors.Wrap(err, "failed to calculate tx transaction for signing message")
+	}
+
+	for _, signature := range signatures {
+		ed25519Signature, isEd25519Signature := signature.(*iotago.Ed25519Signature)
+		if !isEd25519Signature {
+			return ierrors.Wrapf(iotago.ErrUnknownSignatureType, "type %T", signature)
+		}
+
+		signerAddr := iotago.Ed25519AddressFromPubKey(ed25519Signature.PublicKey[:])
+		if err := ed25519Signature.Valid(txEssenceData, signerAddr); err != nil {
+			return ierrors.Wrapf(err, "invalid signature of address %s", signerAddr)
+		}
+
+		if _, exists := p.signatureForAddress(signerAddr); exists {
+			continue
+		}
+
+		p.Signatures = append(p.Signatures, signature)
+	}
+
+	return nil
+}
+
+// Combine adds the signatures of the given PartiallySignedTransactions of the same transaction.
+func (p *PartiallySignedTransaction) Combine(others ...*PartiallySignedTransaction) error {
+	txID, err := p.Transaction.ID()
+	if err != nil {
+		return ierrors.Wrap(err, "failed to compute transaction ID")
+	}
+
+	for _, other := range others {
+		otherTxID, err := other.Transaction.ID()
+		if err != nil {
+			return ierrors.Wrap(err, "failed to compute transaction ID")
+		}
+
+		if txID != otherTxID {
+			return ierrors.Wrapf(ErrPartiallySignedTransactionMismatch, "transaction ID %s != %s", txID, otherTxID)
+		}
+
+		if err := p.AddSignatures(other.Signatures...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Finalize produces the SignedTransaction with the unlocks for all inputs from the collected signatures.
+// It fails if not all inputs can be unlocked with the signatures collected so far.
+func (p *PartiallySignedTransaction) Finalize() (*iotago.SignedTransaction, error) {
+	if len(p.Inputs) != len(p.Transaction.TransactionEssence.Inputs) {
+		return nil, ierrors.Wrapf(ErrPartiallySignedTransactionInputsInvalid, "input count %d != %d", len(p.Inputs), len(p.Transaction.TransactionEssence.Inputs))
+	}
+
+	inputSet := make(iotago.OutputSet, len(p.Inputs))
+	inputOwner := make(map[iotago.OutputID]iotago.Address, len(p.Inputs))
+	for i, input := range p.Inputs {
+		//nolint:forcetypeassert // we can safely assume that this is an UTXOInput
+		if input.InputID != p.Transaction.TransactionEssence.Inputs[i].(*iotago.UTXOInput).OutputID() {
+			return nil, ierrors.Wrapf(ErrPartiallySignedTransactionInputsInvalid, "input %d has ID %s", i, input.InputID)
+		}
+
+		inputSet[input.InputID] = input.Input
+		inputOwner[input.InputID] = input.UnlockTarget
+	}
+
+	unlocks, err := buildUnlocks(p.Transaction, inputSet, inputOwner, iotago.AddressSignerFunc(func(addr iotago.Address, _ []byte) (iotago.Signature, error) {
+		signature, exists := p.signatureForAddress(addr)
+		if !exists {
+			return nil, ierrors.Errorf("no signature collected for address %s: %w", addr, iotago.ErrAddressKeysNotMapped)
+		}
+
+		return signature, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &iotago.SignedTransaction{
+		API:         p.API,
+		Transaction: p.Transaction,
+		Unlocks:     unlocks,
+	}, nil
+}
+
+// signatureForAddress returns the collected signature which unlocks the given address.
+func (p *PartiallySignedTransaction) signatureForAddress(addr iotago.Address) (iotago.Signature, bool) {
+	var signerAddr *iotago.Ed25519Address
+	switch address := resolveUnderlyingAddress(addr).(type) {
+	case *iotago.Ed25519Address:
+		signerAddr = address
+	case *iotago.ImplicitAccountCreationAddress:
+		signerAddr = (*iotago.Ed25519Address)(address)
+	default:
+		return nil, false
+	}
+
+	for _, signature := range p.Signatures {
+		ed25519Signature, isEd25519Signature := signature.(*iotago.Ed25519Signature)
+		if !isEd25519Signature {
+			continue
+		}
+
+		if iotago.Ed25519AddressFromPubKey(ed25519Signature.PublicKey[:]).Equal(signerAddr) {
+			return signature, true
+		}
+	}
+
+	return nil, false
+}
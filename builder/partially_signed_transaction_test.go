@@ -0,0 +1,100 @@
+package builder_test
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/builder"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+	"github.com/iotaledger/iota.go/v4/vm"
+)
+
+func TestPartiallySignedTransaction(t *testing.T) {
+	identities := make([]ed25519.PrivateKey, 3)
+	addrs := make([]*iotago.Ed25519Address, 3)
+	for i := range identities {
+		identities[i] = tpkg.RandEd25519PrivateKey()
+		//nolint:forcetypeassert // we can safely assume that this is an ed25519.PublicKey
+		addrs[i] = iotago.Ed25519AddressFromPubKey(identities[i].Public().(ed25519.PublicKey))
+	}
+
+	multiAddr := iotago.NewMultiAddress(iotago.AddressesWithWeight{
+		{Address: addrs[0], Weight: 1},
+		{Address: addrs[1], Weight: 1},
+		{Address: addrs[2], Weight: 1},
+	}, 2)
+
+	inputIDs := tpkg.RandOutputIDs(2)
+	inputs := vm.InputSet{
+		inputIDs[0]: &iotago.BasicOutput{
+			Amount:           1000,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: multiAddr}},
+		},
+		inputIDs[1]: &iotago.BasicOutput{
+			Amount:           1000,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: addrs[2]}},
+		},
+	}
+
+	bdl := builder.NewTransactionBuilder(tpkg.ZeroCostTestAPI)
+	for _, inputID := range inputIDs {
+		bdl.AddInput(&builder.TxInput{UnlockTarget: inputs[inputID].UnlockConditionSet().Address().Address, InputID: inputID, Input: inputs[inputID]})
+	}
+	bdl.AddOutput(&iotago.BasicOutput{
+		Amount:           2000,
+		UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: tpkg.RandEd25519Address()}},
+	})
+
+	unsignedTx, err := bdl.BuildPartiallySigned()
+	require.NoError(t, err)
+
+	unsignedTxBytes, err := unsignedTx.Bytes()
+	require.NoError(t, err)
+
+	unsignedTxJSON, err := unsignedTx.JSONEncode()
+	require.NoError(t, err)
+
+	// the first co-signer receives the binary encoding
+	txSignerOne, consumedBytes, err := builder.PartiallySignedTransactionFromBytes(tpkg.ZeroCostTestAPI)(unsignedTxBytes)
+	require.NoError(t, err)
+	require.Equal(t, len(unsignedTxBytes), consumedBytes)
+	require.NoError(t, txSignerOne.Sign(iotago.NewInMemoryAddressSigner(iotago.NewAddressKeysForEd25519Address(addrs[0], identities[0]))))
+	require.Len(t, txSignerOne.Signatures, 1)
+
+	// not enough signatures collected yet
+	_, err = txSignerOne.Finalize()
+	require.ErrorIs(t, err, iotago.ErrMultiAddressUnlockThresholdNotReached)
+
+	// the second co-signer receives the JSON encoding
+	txSignerTwo, err := builder.PartiallySignedTransactionFromJSON(tpkg.ZeroCostTestAPI, unsignedTxJSON)
+	require.NoError(t, err)
+	require.NoError(t, txSignerTwo.Sign(iotago.NewInMemoryAddressSigner(iotago.NewAddressKeysForEd25519Address(addrs[2], identities[2]))))
+	require.Len(t, txSignerTwo.Signatures, 1)
+
+	txSignerTwoBytes, err := txSignerTwo.Bytes()
+	require.NoError(t, err)
+
+	txSignerTwo, _, err = builder.PartiallySignedTransactionFromBytes(tpkg.ZeroCostTestAPI)(txSignerTwoBytes)
+	require.NoError(t, err)
+
+	// combining the same signatures twice doesn't add duplicates
+	require.NoError(t, unsignedTx.Combine(txSignerOne, txSignerTwo, txSignerTwo))
+	require.Len(t, unsignedTx.Signatures, 2)
+
+	signedTx, err := unsignedTx.Finalize()
+	require.NoError(t, err)
+
+	_, err = vm.ValidateUnlocks(signedTx, vm.ResolvedInputs{InputSet: inputs})
+	require.NoError(t, err)
+
+	// the signatures of a different transaction can't be combined
+	otherTx, err := bdl.Clone().AddTaggedDataPayload(&iotago.TaggedData{Tag: []byte("other")}).BuildPartiallySigned()
+	require.NoError(t, err)
+	require.ErrorIs(t, otherTx.Combine(unsignedTx), builder.ErrPartiallySignedTransactionMismatch)
+
+	// signatures over a different transaction are rejected
+	require.Error(t, otherTx.AddSignatures(unsignedTx.Signatures...))
+}
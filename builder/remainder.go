@@ -0,0 +1,220 @@
+package builder
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/iotaledger/hive.go/core/safemath"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+)
+
+// RemainderOptions defines the options for the creation of remainder outputs.
+type RemainderOptions struct {
+	mergeDust bool
+}
+
+// WithRemainderDustMerging defines whether leftover base tokens and mana, which don't cover the storage deposit
+// of a separate remainder output, may be added to an existing output of the transaction owned by the remainder address.
+// Enabled by default.
+func WithRemainderDustMerging(mergeDust bool) options.Option[RemainderOptions] {
+	return func(o *RemainderOptions) {
+		o.mergeDust = mergeDust
+	}
+}
+
+// AddRemainderOutputs adds BasicOutputs owned by the remainder address for the base tokens, native tokens and
+// unbound mana of the inputs which are not consumed by the outputs and allotments of the transaction.
+// Every remainder output holds at most one NativeTokenFeature, the leftover base tokens are used to cover
+// the storage deposit of the remainder outputs and the remaining base tokens and mana are added to the first one.
+//
+// If the leftover base tokens don't cover the storage deposit of a separate remainder output,
+// they are added to an existing output of the transaction owned by the remainder address instead, see WithRemainderDustMerging.
+func (b *TransactionBuilder) AddRemainderOutputs(targetSlot iotago.SlotIndex, remainderAddress iotago.Address, opts ...options.Option[RemainderOptions]) *TransactionBuilder {
+	setBuildError := func(err error) *TransactionBuilder {
+		b.occurredBuildErr = err
+		return b
+	}
+
+	if b.occurredBuildErr != nil {
+		return b
+	}
+
+	remainderOpts := options.Apply(&RemainderOptions{
+		mergeDust: true,
+	}, opts)
+
+	leftoverBaseTokens, leftoverNativeTokens, err := b.leftoverBaseTokensAndNativeTokens()
+	if err != nil {
+		return setBuildError(err)
+	}
+
+	leftoverMana, err := b.calculateAvailableManaLeftover(targetSlot, 0, iotago.EmptyAccountID)
+	if err != nil {
+		return setBuildError(err)
+	}
+
+	if leftoverBaseTokens == 0 && leftoverMana == 0 && len(leftoverNativeTokens) == 0 {
+		return b
+	}
+
+	remainderOutputs, requiredStorageDeposit, err := b.remainderOutputs(remainderAddress, leftoverNativeTokens)
+	if err != nil {
+		return setBuildError(err)
+	}
+
+	if leftoverBaseTokens >= requiredStorageDeposit {
+		remainderOutputs[0].Amount += leftoverBaseTokens - requiredStorageDeposit
+		remainderOutputs[0].Mana = leftoverMana
+
+		for _, remainderOutput := range remainderOutputs {
+			b.AddOutput(remainderOutput)
+		}
+
+		return b
+	}
+
+	// the native tokens can't be merged, because every output can only hold a single native token
+	if len(leftoverNativeTokens) > 0 || !remainderOpts.mergeDust {
+		return setBuildError(ierrors.Wrapf(iotago.ErrStorageDepositNotCovered, "leftover base tokens %d do not cover the storage deposit of the remainder outputs %d", leftoverBaseTokens, requiredStorageDeposit))
+	}
+
+	dustOutputIndex := b.dustOutputIndex(remainderAddress)
+	if dustOutputIndex == -1 {
+		return setBuildError(ierrors.Wrapf(iotago.ErrStorageDepositNotCovered, "leftover base tokens %d do not cover the storage deposit of the remainder output %d and no output to merge them into exists", leftoverBaseTokens, requiredStorageDeposit))
+	}
+
+	// merge the dust into the existing output
+	var amount *iotago.BaseToken
+	var mana *iotago.Mana
+	switch output := b.transaction.Outputs[dustOutputIndex].(type) {
+	case *iotago.BasicOutput:
+		amount, mana = &output.Amount, &output.Mana
+	case *iotago.AccountOutput:
+		amount, mana = &output.Amount, &output.Mana
+	case *iotago.NFTOutput:
+		amount, mana = &output.Amount, &output.Mana
+	}
+
+	mergedAmount, err := safemath.SafeAdd(*amount, leftoverBaseTokens)
+	if err != nil {
+		return setBuildError(ierrors.Wrap(err, "failed to add the leftover base tokens to the existing output"))
+	}
+
+	mergedMana, err := safemath.SafeAdd(*mana, leftoverMana)
+	if err != nil {
+		return setBuildError(ierrors.Wrap(err, "failed to add the leftover mana to the existing output"))
+	}
+
+	*amount, *mana = mergedAmount, mergedMana
+
+	return b
+}
+
+// remainderOutputs returns the BasicOutputs owned by the remainder address which are needed to hold the given
+// leftover native tokens, funded with their minimum storage deposit, and the sum of their storage deposits.
+// Every output can only hold a single native token, so at least one output is returned.
+func (b *TransactionBuilder) remainderOutputs(remainderAddress iotago.Address, leftoverNativeTokens []*iotago.NativeTokenFeature) ([]*iotago.BasicOutput, iotago.BaseToken, error) {
+	newRemainderOutput := func() *iotago.BasicOutput {
+		return &iotago.BasicOutput{
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: remainderAddress}},
+			Features:         iotago.BasicOutputFeatures{},
+		}
+	}
+
+	remainderOutputs := make([]*iotago.BasicOutput, 0, len(leftoverNativeTokens))
+	for _, nativeToken := range leftoverNativeTokens {
+		remainderOutput := newRemainderOutput()
+		remainderOutput.Features = iotago.BasicOutputFeatures{nativeToken}
+		remainderOutputs = append(remainderOutputs, remainderOutput)
+	}
+	if len(remainderOutputs) == 0 {
+		remainderOutputs = append(remainderOutputs, newRemainderOutput())
+	}
+
+	var requiredStorageDeposit iotago.BaseToken
+	for _, remainderOutput := range remainderOutputs {
+		minDeposit, err := b.api.StorageScoreStructure().MinDeposit(remainderOutput)
+		if err != nil {
+			return nil, 0, ierrors.Wrap(err, "failed to calculate the storage deposit of the remainder output")
+		}
+
+		remainderOutput.Amount = minDeposit
+		if requiredStorageDeposit, err = safemath.SafeAdd(requiredStorageDeposit, minDeposit); err != nil {
+			return nil, 0, ierrors.Wrap(err, "failed to sum the storage deposit of the remainder outputs")
+		}
+	}
+
+	return remainderOutputs, requiredStorageDeposit, nil
+}
+
+// dustOutputIndex returns the index of the output owned by the remainder address which the dust should be merged into.
+// Outputs of other addresses are never used, since that would give the leftover away.
+// Returns -1 if no suitable output exists.
+func (b *TransactionBuilder) dustOutputIndex(remainderAddress iotago.Address) int {
+	for i, output := range b.transaction.Outputs {
+		switch output.(type) {
+		case *iotago.BasicOutput, *iotago.AccountOutput, *iotago.NFTOutput:
+		default:
+			continue
+		}
+
+		if addressUnlockCondition := output.UnlockConditionSet().Address(); addressUnlockCondition != nil && addressUnlockCondition.Address.Equal(remainderAddress) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// leftoverBaseTokensAndNativeTokens returns the base tokens and native tokens of the inputs
+// which are not consumed by the outputs. The native tokens are sorted by their ID.
+func (b *TransactionBuilder) leftoverBaseTokensAndNativeTokens() (iotago.BaseToken, []*iotago.NativeTokenFeature, error) {
+	var err error
+
+	var inputBaseTokens, outputBaseTokens iotago.BaseToken
+	nativeTokens := make(iotago.NativeTokenSum)
+
+	for _, input := range b.inputs {
+		if inputBaseTokens, err = safemath.SafeAdd(inputBaseTokens, input.BaseTokenAmount()); err != nil {
+			return 0, nil, ierrors.Wrap(err, "failed to sum the base tokens of the inputs")
+		}
+
+		if nativeToken := input.FeatureSet().NativeToken(); nativeToken != nil {
+			nativeTokens[nativeToken.ID] = new(big.Int).Add(nativeTokens.ValueOrBigInt0(nativeToken.ID), nativeToken.Amount)
+		}
+	}
+
+	for _, output := range b.transaction.Outputs {
+		if outputBaseTokens, err = safemath.SafeAdd(outputBaseTokens, output.BaseTokenAmount()); err != nil {
+			return 0, nil, ierrors.Wrap(err, "failed to sum the base tokens of the outputs")
+		}
+
+		if nativeToken := output.FeatureSet().NativeToken(); nativeToken != nil {
+			nativeTokens[nativeToken.ID] = new(big.Int).Sub(nativeTokens.ValueOrBigInt0(nativeToken.ID), nativeToken.Amount)
+		}
+	}
+
+	leftoverBaseTokens, err := safemath.SafeSub(inputBaseTokens, outputBaseTokens)
+	if err != nil {
+		return 0, nil, ierrors.Wrapf(iotago.ErrInputOutputSumMismatch, "outputs %d exceed inputs %d", outputBaseTokens, inputBaseTokens)
+	}
+
+	// native tokens which are minted on the output side don't need a remainder
+	leftoverNativeTokens := make([]*iotago.NativeTokenFeature, 0)
+	for nativeTokenID, amount := range nativeTokens {
+		if amount.Sign() <= 0 {
+			continue
+		}
+
+		leftoverNativeTokens = append(leftoverNativeTokens, &iotago.NativeTokenFeature{ID: nativeTokenID, Amount: amount})
+	}
+
+	sort.Slice(leftoverNativeTokens, func(i, j int) bool {
+		return bytes.Compare(leftoverNativeTokens[i].ID[:], leftoverNativeTokens[j].ID[:]) < 0
+	})
+
+	return leftoverBaseTokens, leftoverNativeTokens, nil
+}
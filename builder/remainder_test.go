@@ -0,0 +1,140 @@
+package builder_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/builder"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+func TestTransactionBuilderAddRemainderOutputs(t *testing.T) {
+	testAPI := iotago.V3API(tpkg.IOTAMainnetV3TestProtocolParameters)
+
+	ownerAddr := tpkg.RandEd25519Address()
+	remainderAddr := tpkg.RandEd25519Address()
+	nativeTokenIDs := []iotago.NativeTokenID{tpkg.RandNativeTokenID(), tpkg.RandNativeTokenID()}
+
+	basicOutput := func(addr iotago.Address, amount iotago.BaseToken, features ...iotago.BasicOutputFeature) *iotago.BasicOutput {
+		return &iotago.BasicOutput{
+			Amount:           amount,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: addr}},
+			Features:         features,
+		}
+	}
+
+	minDeposit := func(output iotago.Output) iotago.BaseToken {
+		deposit, err := testAPI.StorageScoreStructure().MinDeposit(output)
+		require.NoError(t, err)
+
+		return deposit
+	}
+
+	newBuilder := func(inputs []iotago.Output, outputs []iotago.Output) *builder.TransactionBuilder {
+		bdl := builder.NewTransactionBuilder(testAPI)
+		for _, input := range inputs {
+			bdl.AddInput(&builder.TxInput{UnlockTarget: ownerAddr, InputID: tpkg.RandOutputID(0), Input: input})
+		}
+		for _, output := range outputs {
+			bdl.AddOutput(output)
+		}
+
+		return bdl
+	}
+
+	t.Run("ok - base tokens and native tokens", func(t *testing.T) {
+		recipientOutput := basicOutput(tpkg.RandEd25519Address(), 1_000_000, &iotago.NativeTokenFeature{ID: nativeTokenIDs[0], Amount: big.NewInt(4)})
+
+		bdl := newBuilder([]iotago.Output{
+			basicOutput(ownerAddr, 5_000_000, &iotago.NativeTokenFeature{ID: nativeTokenIDs[0], Amount: big.NewInt(10)}),
+			basicOutput(ownerAddr, 5_000_000, &iotago.NativeTokenFeature{ID: nativeTokenIDs[1], Amount: big.NewInt(5)}),
+		}, []iotago.Output{recipientOutput}).AddRemainderOutputs(0, remainderAddr)
+
+		tx, err := bdl.Build(&iotago.EmptyAddressSigner{})
+		require.NoError(t, err)
+
+		// one remainder output per native token
+		require.Len(t, tx.Transaction.Outputs, 3)
+
+		var outputBaseTokens iotago.BaseToken
+		remainderNativeTokens := make(iotago.NativeTokenSum)
+		for _, output := range tx.Transaction.Outputs {
+			outputBaseTokens += output.BaseTokenAmount()
+			require.GreaterOrEqual(t, output.BaseTokenAmount(), minDeposit(output))
+
+			if output == recipientOutput {
+				continue
+			}
+
+			require.True(t, output.UnlockConditionSet().Address().Address.Equal(remainderAddr))
+			nativeToken := output.FeatureSet().NativeToken()
+			require.NotNil(t, nativeToken)
+			remainderNativeTokens[nativeToken.ID] = nativeToken.Amount
+		}
+
+		require.EqualValues(t, 10_000_000, outputBaseTokens)
+		require.EqualValues(t, 6, remainderNativeTokens[nativeTokenIDs[0]].Int64())
+		require.EqualValues(t, 5, remainderNativeTokens[nativeTokenIDs[1]].Int64())
+	})
+
+	t.Run("ok - nothing left over", func(t *testing.T) {
+		bdl := newBuilder([]iotago.Output{basicOutput(ownerAddr, 1_000_000)}, []iotago.Output{basicOutput(tpkg.RandEd25519Address(), 1_000_000)}).
+			AddRemainderOutputs(0, remainderAddr)
+
+		tx, err := bdl.Build(&iotago.EmptyAddressSigner{})
+		require.NoError(t, err)
+		require.Len(t, tx.Transaction.Outputs, 1)
+	})
+
+	t.Run("err - dust is not merged into outputs of other addresses", func(t *testing.T) {
+		recipientOutput := basicOutput(tpkg.RandEd25519Address(), 1_000_000)
+
+		bdl := newBuilder([]iotago.Output{basicOutput(ownerAddr, 1_000_001)}, []iotago.Output{recipientOutput}).
+			AddRemainderOutputs(0, remainderAddr)
+
+		_, err := bdl.Build(&iotago.EmptyAddressSigner{})
+		require.ErrorIs(t, err, iotago.ErrStorageDepositNotCovered)
+		require.EqualValues(t, 1_000_000, recipientOutput.Amount)
+	})
+
+	t.Run("ok - dust merged into output owned by the remainder address", func(t *testing.T) {
+		recipientOutput := basicOutput(tpkg.RandEd25519Address(), 1_000_000)
+		ownOutput := basicOutput(remainderAddr, 1_000_000)
+
+		bdl := newBuilder([]iotago.Output{basicOutput(ownerAddr, 2_000_001)}, []iotago.Output{recipientOutput, ownOutput}).
+			AddRemainderOutputs(0, remainderAddr)
+
+		tx, err := bdl.Build(&iotago.EmptyAddressSigner{})
+		require.NoError(t, err)
+		require.Len(t, tx.Transaction.Outputs, 2)
+		require.EqualValues(t, 1_000_000, tx.Transaction.Outputs[0].BaseTokenAmount())
+		require.EqualValues(t, 1_000_001, tx.Transaction.Outputs[1].BaseTokenAmount())
+	})
+
+	t.Run("err - dust merging disabled", func(t *testing.T) {
+		bdl := newBuilder([]iotago.Output{basicOutput(ownerAddr, 1_000_001)}, []iotago.Output{basicOutput(tpkg.RandEd25519Address(), 1_000_000)}).
+			AddRemainderOutputs(0, remainderAddr, builder.WithRemainderDustMerging(false))
+
+		_, err := bdl.Build(&iotago.EmptyAddressSigner{})
+		require.ErrorIs(t, err, iotago.ErrStorageDepositNotCovered)
+	})
+
+	t.Run("err - native tokens without storage deposit", func(t *testing.T) {
+		bdl := newBuilder([]iotago.Output{basicOutput(ownerAddr, 1_000_001, &iotago.NativeTokenFeature{ID: nativeTokenIDs[0], Amount: big.NewInt(10)})}, []iotago.Output{basicOutput(tpkg.RandEd25519Address(), 1_000_000)}).
+			AddRemainderOutputs(0, remainderAddr)
+
+		_, err := bdl.Build(&iotago.EmptyAddressSigner{})
+		require.ErrorIs(t, err, iotago.ErrStorageDepositNotCovered)
+	})
+
+	t.Run("err - outputs exceed inputs", func(t *testing.T) {
+		bdl := newBuilder([]iotago.Output{basicOutput(ownerAddr, 1_000_000)}, []iotago.Output{basicOutput(tpkg.RandEd25519Address(), 2_000_000)}).
+			AddRemainderOutputs(0, remainderAddr)
+
+		_, err := bdl.Build(&iotago.EmptyAddressSigner{})
+		require.ErrorIs(t, err, iotago.ErrInputOutputSumMismatch)
+	})
+}
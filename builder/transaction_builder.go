@@ -62,6 +62,7 @@ func (b *TransactionBuilder) Clone() *TransactionBuilder {
 		transaction:      b.transaction.Clone(),
 		inputs:           b.inputs.Clone(),
 		inputOwner:       cpyInputOwner,
+		rewards:          b.rewards,
 	}
 }
 
@@ -550,6 +551,8 @@ func (b *TransactionBuilder) MinRequiredAllotedMana(workScoreParameters *iotago.
 }
 
 // Build sings the inputs with the given signer and returns the built payload.
+// Inputs owned by a MultiAddress are unlocked with a MultiUnlock containing the signatures of the addresses
+// known to the signer, until the threshold of the MultiAddress is reached.
 func (b *TransactionBuilder) Build(signer iotago.AddressSigner) (*iotago.SignedTransaction, error) {
 	switch {
 	case b.occurredBuildErr != nil:
@@ -561,42 +564,72 @@ func (b *TransactionBuilder) Build(signer iotago.AddressSigner) (*iotago.SignedT
 	b.transaction.Allotments.Sort()
 	b.transaction.TransactionEssence.ContextInputs.Sort()
 
+	unlocks, err := buildUnlocks(b.transaction, b.inputs, b.inputOwner, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	sigTxPayload := &iotago.SignedTransaction{
+		API:         b.api,
+		Transaction: b.transaction,
+		Unlocks:     unlocks,
+	}
+
+	return sigTxPayload, nil
+}
+
+// buildUnlocks produces the unlocks for the inputs of the given transaction with the given signer.
+func buildUnlocks(transaction *iotago.Transaction, inputSet iotago.OutputSet, inputOwner map[iotago.OutputID]iotago.Address, signer iotago.AddressSigner) (iotago.Unlocks, error) {
 	// prepare the inputs commitment in the same order as the inputs in the essence
 	var inputIDs iotago.OutputIDs
-	for _, input := range b.transaction.TransactionEssence.Inputs {
+	for _, input := range transaction.TransactionEssence.Inputs {
 		//nolint:forcetypeassert // we can safely assume that this is an UTXOInput
 		inputIDs = append(inputIDs, input.(*iotago.UTXOInput).OutputID())
 	}
 
-	inputs := inputIDs.OrderedSet(b.inputs)
+	inputs := inputIDs.OrderedSet(inputSet)
 
-	txEssenceData, err := b.transaction.SigningMessage()
+	txEssenceData, err := transaction.SigningMessage()
 	if err != nil {
 		return nil, ierrors.Wrap(err, "failed to calculate tx transaction for signing message")
 	}
 
 	unlockPos := map[string]int{}
 	unlocks := iotago.Unlocks{}
-	for i, inputRef := range b.transaction.TransactionEssence.Inputs {
-		//nolint:forcetypeassert // we can safely assume that this is an UTXOInput
-		addr := b.inputOwner[inputRef.(*iotago.UTXOInput).OutputID()]
+	for i, inputID := range inputIDs {
+		owner := inputOwner[inputID]
+
+		// restricted addresses are unlocked like their underlying address
+		addr := resolveUnderlyingAddress(owner)
 		addrKey := addr.Key()
 
 		pos, unlocked := unlockPos[addrKey]
 		if !unlocked {
-			// the output's owning chain address must have been unlocked already
-			if _, is := addr.(iotago.ChainAddress); is {
+			var unlock iotago.Unlock
+
+			switch address := addr.(type) {
+			case iotago.ChainAddress:
+				// the output's owning chain address must have been unlocked already
 				return nil, ierrors.Errorf("input %d's owning chain is not unlocked, chainID %s, type %s", i, addr, addr.Type())
-			}
 
-			// produce signature
-			var signature iotago.Signature
-			signature, err = signer.Sign(addr, txEssenceData)
-			if err != nil {
-				return nil, ierrors.Wrapf(err, "failed to sign tx transaction: %s", txEssenceData)
+			case *iotago.MultiAddress:
+				unlock, err = multiUnlock(address, signer, txEssenceData, unlockPos)
+				if err != nil {
+					return nil, ierrors.Wrapf(err, "failed to unlock input %d's multi address", i)
+				}
+
+			default:
+				// produce signature
+				var signature iotago.Signature
+				signature, err = signer.Sign(owner, txEssenceData)
+				if err != nil {
+					return nil, ierrors.Wrapf(err, "failed to sign tx transaction: %s", txEssenceData)
+				}
+
+				unlock = &iotago.SignatureUnlock{Signature: signature}
 			}
 
-			unlocks = append(unlocks, &iotago.SignatureUnlock{Signature: signature})
+			unlocks = append(unlocks, unlock)
 			addChainAsUnlocked(inputs[i], i, unlockPos)
 			unlockPos[addrKey] = i
 
@@ -607,28 +640,97 @@ func (b *TransactionBuilder) Build(signer iotago.AddressSigner) (*iotago.SignedT
 		addChainAsUnlocked(inputs[i], i, unlockPos)
 	}
 
-	sigTxPayload := &iotago.SignedTransaction{
-		API:         b.api,
-		Transaction: b.transaction,
-		Unlocks:     unlocks,
-	}
-
-	return sigTxPayload, nil
+	return unlocks, nil
 }
 
 func addReferentialUnlock(addr iotago.Address, unlocks iotago.Unlocks, pos int) iotago.Unlocks {
+	return append(unlocks, referentialUnlock(addr, pos))
+}
+
+func referentialUnlock(addr iotago.Address, pos int) iotago.Unlock {
 	switch addr.(type) {
 	case *iotago.AccountAddress:
-		return append(unlocks, &iotago.AccountUnlock{Reference: uint16(pos)})
+		return &iotago.AccountUnlock{Reference: uint16(pos)}
 	case *iotago.AnchorAddress:
-		return append(unlocks, &iotago.AnchorUnlock{Reference: uint16(pos)})
+		return &iotago.AnchorUnlock{Reference: uint16(pos)}
 	case *iotago.NFTAddress:
-		return append(unlocks, &iotago.NFTUnlock{Reference: uint16(pos)})
+		return &iotago.NFTUnlock{Reference: uint16(pos)}
 	default:
-		return append(unlocks, &iotago.ReferenceUnlock{Reference: uint16(pos)})
+		return &iotago.ReferenceUnlock{Reference: uint16(pos)}
 	}
 }
 
+// multiUnlock produces a MultiUnlock for the given MultiAddress.
+// Addresses that were already unlocked by a previous input are unlocked by reference,
+// the remaining addresses are signed by the signer until the threshold is reached.
+// Addresses which are not needed or can't be signed by the signer get an EmptyUnlock.
+func multiUnlock(addr *iotago.MultiAddress, signer iotago.AddressSigner, txEssenceData []byte, unlockPos map[string]int) (*iotago.MultiUnlock, error) {
+	unlocks := make([]iotago.Unlock, len(addr.Addresses))
+	var cumulativeWeight uint16
+
+	// referential unlocks don't need an additional signature, so we use them first
+	for i, member := range addr.Addresses {
+		if cumulativeWeight >= addr.Threshold {
+			break
+		}
+
+		if pos, unlocked := unlockPos[member.Address.Key()]; unlocked {
+			unlocks[i] = referentialUnlock(member.Address, pos)
+			cumulativeWeight += uint16(member.Weight)
+		}
+	}
+
+	for i, member := range addr.Addresses {
+		if cumulativeWeight >= addr.Threshold {
+			break
+		}
+
+		if unlocks[i] != nil {
+			continue
+		}
+
+		// chain addresses can only be unlocked by reference
+		if _, isChainAddress := member.Address.(iotago.ChainAddress); isChainAddress {
+			continue
+		}
+
+		signature, err := signer.Sign(member.Address, txEssenceData)
+		if err != nil {
+			// the signer doesn't know the keys of this address, maybe other addresses reach the threshold
+			if ierrors.Is(err, iotago.ErrAddressKeysNotMapped) {
+				continue
+			}
+
+			return nil, ierrors.Wrapf(err, "failed to sign tx transaction for address %d of the multi address", i)
+		}
+
+		unlocks[i] = &iotago.SignatureUnlock{Signature: signature}
+		cumulativeWeight += uint16(member.Weight)
+	}
+
+	if cumulativeWeight < addr.Threshold {
+		return nil, ierrors.Wrapf(iotago.ErrMultiAddressUnlockThresholdNotReached, "cumulative weight of the unlocked addresses %d < threshold %d", cumulativeWeight, addr.Threshold)
+	}
+
+	// maintain the index relationship between the addresses and the unlocks
+	for i := range unlocks {
+		if unlocks[i] == nil {
+			unlocks[i] = &iotago.EmptyUnlock{}
+		}
+	}
+
+	return &iotago.MultiUnlock{Unlocks: unlocks}, nil
+}
+
+// resolveUnderlyingAddress returns the underlying address in case of a restricted address.
+func resolveUnderlyingAddress(addr iotago.Address) iotago.Address {
+	if restrictedAddr, is := addr.(*iotago.RestrictedAddress); is {
+		return restrictedAddr.Address
+	}
+
+	return addr
+}
+
 func addChainAsUnlocked(input iotago.Output, posUnlocked int, prevUnlocked map[string]int) {
 	if chainInput, is := input.(iotago.ChainOutput); is && chainInput.ChainID().Addressable() {
 		prevUnlocked[chainInput.ChainID().ToAddress().Key()] = posUnlocked
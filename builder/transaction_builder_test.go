@@ -6,11 +6,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/iotaledger/hive.go/ierrors"
 	iotago "github.com/iotaledger/iota.go/v4"
 	"github.com/iotaledger/iota.go/v4/builder"
 	"github.com/iotaledger/iota.go/v4/tpkg"
+	"github.com/iotaledger/iota.go/v4/vm"
 )
 
 func TestTransactionBuilder(t *testing.T) {
@@ -185,3 +187,102 @@ func TestTransactionBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionBuilderMultiAddress(t *testing.T) {
+	identityOne := tpkg.RandEd25519PrivateKey()
+	identityTwo := tpkg.RandEd25519PrivateKey()
+	identityThree := tpkg.RandEd25519PrivateKey()
+
+	//nolint:forcetypeassert // we can safely assume that this is an ed25519.PublicKey
+	addrOne := iotago.Ed25519AddressFromPubKey(identityOne.Public().(ed25519.PublicKey))
+	//nolint:forcetypeassert // we can safely assume that this is an ed25519.PublicKey
+	addrTwo := iotago.Ed25519AddressFromPubKey(identityTwo.Public().(ed25519.PublicKey))
+	//nolint:forcetypeassert // we can safely assume that this is an ed25519.PublicKey
+	addrThree := iotago.Ed25519AddressFromPubKey(identityThree.Public().(ed25519.PublicKey))
+
+	multiAddr := iotago.NewMultiAddress(iotago.AddressesWithWeight{
+		{Address: addrOne, Weight: 1},
+		{Address: addrTwo, Weight: 1},
+		{Address: addrThree, Weight: 1},
+	}, 2)
+	restrictedMultiAddr := iotago.RestrictedAddressWithCapabilities(multiAddr)
+
+	basicOutput := func(addr iotago.Address) *iotago.BasicOutput {
+		return &iotago.BasicOutput{
+			Amount:           1000,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: addr}},
+		}
+	}
+
+	newBuilder := func() (*builder.TransactionBuilder, vm.ResolvedInputs) {
+		inputIDs := tpkg.RandOutputIDs(3)
+		inputs := vm.InputSet{
+			inputIDs[0]: basicOutput(addrOne),
+			inputIDs[1]: basicOutput(multiAddr),
+			inputIDs[2]: basicOutput(restrictedMultiAddr),
+		}
+
+		bdl := builder.NewTransactionBuilder(tpkg.ZeroCostTestAPI)
+		for _, inputID := range inputIDs {
+			bdl.AddInput(&builder.TxInput{UnlockTarget: inputs[inputID].UnlockConditionSet().Address().Address, InputID: inputID, Input: inputs[inputID]})
+		}
+		bdl.AddOutput(basicOutput(tpkg.RandEd25519Address()))
+
+		return bdl, vm.ResolvedInputs{InputSet: inputs}
+	}
+
+	t.Run("ok - threshold reached with referential and signature unlocks", func(t *testing.T) {
+		bdl, resolvedInputs := newBuilder()
+
+		tx, err := bdl.Build(iotago.NewInMemoryAddressSigner(
+			iotago.NewAddressKeysForEd25519Address(addrOne, identityOne),
+			iotago.NewAddressKeysForEd25519Address(addrTwo, identityTwo),
+		))
+		require.NoError(t, err)
+
+		_, err = vm.ValidateUnlocks(tx, resolvedInputs)
+		require.NoError(t, err)
+
+		// the first address was already unlocked by the first input, the third address is not needed
+		require.Len(t, tx.Unlocks, 3)
+		require.IsType(t, &iotago.SignatureUnlock{}, tx.Unlocks[0])
+		require.IsType(t, &iotago.MultiUnlock{}, tx.Unlocks[1])
+		//nolint:forcetypeassert // we already checked the type
+		multiUnlock := tx.Unlocks[1].(*iotago.MultiUnlock)
+		require.Len(t, multiUnlock.Unlocks, 3)
+		require.Equal(t, &iotago.ReferenceUnlock{Reference: 0}, multiUnlock.Unlocks[0])
+		require.IsType(t, &iotago.SignatureUnlock{}, multiUnlock.Unlocks[1])
+		require.Equal(t, &iotago.EmptyUnlock{}, multiUnlock.Unlocks[2])
+		require.Equal(t, &iotago.ReferenceUnlock{Reference: 1}, tx.Unlocks[2])
+	})
+
+	t.Run("ok - threshold reached with signatures only", func(t *testing.T) {
+		bdl, resolvedInputs := newBuilder()
+
+		tx, err := bdl.Build(iotago.NewInMemoryAddressSigner(
+			iotago.NewAddressKeysForEd25519Address(addrOne, identityOne),
+			iotago.NewAddressKeysForEd25519Address(addrTwo, identityTwo),
+			iotago.NewAddressKeysForEd25519Address(addrThree, identityThree),
+		))
+		require.NoError(t, err)
+
+		_, err = vm.ValidateUnlocks(tx, resolvedInputs)
+		require.NoError(t, err)
+	})
+
+	t.Run("err - threshold not reached", func(t *testing.T) {
+		bdl, _ := newBuilder()
+
+		_, err := bdl.Build(iotago.NewInMemoryAddressSigner(
+			iotago.NewAddressKeysForEd25519Address(addrTwo, identityTwo),
+		))
+		require.ErrorIs(t, err, iotago.ErrAddressKeysNotMapped)
+
+		// the first input can be signed, but the multi address needs more weight
+		bdl, _ = newBuilder()
+		_, err = bdl.Build(iotago.NewInMemoryAddressSigner(
+			iotago.NewAddressKeysForEd25519Address(addrOne, identityOne),
+		))
+		require.ErrorIs(t, err, iotago.ErrMultiAddressUnlockThresholdNotReached)
+	})
+}
@@ -4,10 +4,61 @@ import (
 	"crypto"
 	"fmt"
 
+	"github.com/iotaledger/hive.go/ierrors"
 	"github.com/iotaledger/hive.go/lo"
 	"github.com/iotaledger/iota.go/v4/merklehasher"
 )
 
+var (
+	// ErrUnknownRootsField gets returned when a RootsField is not one of the fields of the Roots.
+	ErrUnknownRootsField = ierrors.New("unknown roots field")
+)
+
+// RootsField identifies a field of the Roots by its position in the merkle tree of the Roots.
+type RootsField byte
+
+const (
+	// RootsFieldTangle identifies the TangleRoot.
+	RootsFieldTangle RootsField = iota
+	// RootsFieldStateMutation identifies the StateMutationRoot.
+	RootsFieldStateMutation
+	// RootsFieldState identifies the StateRoot.
+	RootsFieldState
+	// RootsFieldAccount identifies the AccountRoot.
+	RootsFieldAccount
+	// RootsFieldAttestations identifies the AttestationsRoot.
+	RootsFieldAttestations
+	// RootsFieldCommittee identifies the CommitteeRoot.
+	RootsFieldCommittee
+	// RootsFieldRewards identifies the RewardsRoot.
+	RootsFieldRewards
+	// RootsFieldProtocolParametersHash identifies the ProtocolParametersHash.
+	RootsFieldProtocolParametersHash
+
+	// rootsFieldCount is the amount of leaves of the merkle tree of the Roots.
+	rootsFieldCount = int(RootsFieldProtocolParametersHash) + 1
+)
+
+var rootsFieldNames = [rootsFieldCount]string{
+	"TangleRoot",
+	"StateMutationRoot",
+	"StateRoot",
+	"AccountRoot",
+	"AttestationsRoot",
+	"CommitteeRoot",
+	"RewardsRoot",
+	"ProtocolParametersHash",
+}
+
+// String returns the name of the field of the Roots.
+func (f RootsField) String() string {
+	if int(f) >= rootsFieldCount {
+		return fmt.Sprintf("RootsField(%d)", f)
+	}
+
+	return rootsFieldNames[f]
+}
+
 type Roots struct {
 	TangleRoot             Identifier `serix:""`
 	StateMutationRoot      Identifier `serix:""`
@@ -55,22 +106,64 @@ func (r *Roots) ID() (id Identifier) {
 	)
 }
 
-func (r *Roots) AttestationsProof() *merklehasher.Proof[Identifier] {
+// Get returns the value of the given field.
+func (r *Roots) Get(field RootsField) (Identifier, error) {
+	if int(field) >= rootsFieldCount {
+		return EmptyIdentifier, ierrors.Wrapf(ErrUnknownRootsField, "field %s", field)
+	}
+
+	return r.values()[field], nil
+}
+
+// ProofFor returns the proof of the given field in the merkle tree of the Roots, whose root is the ID of the Roots.
+func (r *Roots) ProofFor(field RootsField) (*merklehasher.Proof[Identifier], error) {
+	if int(field) >= rootsFieldCount {
+		return nil, ierrors.Wrapf(ErrUnknownRootsField, "field %s", field)
+	}
+
 	// We can ignore the error because Identifier.Bytes() will never return an error
 	//nolint:nosnakecase // false positive
-	return lo.PanicOnErr(merklehasher.NewHasher[Identifier](crypto.BLAKE2b_256).ComputeProofForIndex(r.values(), 4))
+	return lo.PanicOnErr(merklehasher.NewHasher[Identifier](crypto.BLAKE2b_256).ComputeProofForIndex(r.values(), int(field))), nil
+}
+
+// AttestationsProof returns the proof of the AttestationsRoot in the merkle tree of the Roots.
+func (r *Roots) AttestationsProof() *merklehasher.Proof[Identifier] {
+	return lo.PanicOnErr(r.ProofFor(RootsFieldAttestations))
 }
 
+// TangleProof returns the proof of the TangleRoot in the merkle tree of the Roots.
 func (r *Roots) TangleProof() *merklehasher.Proof[Identifier] {
-	// We can ignore the error because Identifier.Bytes() will never return an error
-	//nolint:nosnakecase // false positive
-	return lo.PanicOnErr(merklehasher.NewHasher[Identifier](crypto.BLAKE2b_256).ComputeProofForIndex(r.values(), 0))
+	return lo.PanicOnErr(r.ProofFor(RootsFieldTangle))
 }
 
+// MutationProof returns the proof of the StateMutationRoot in the merkle tree of the Roots.
 func (r *Roots) MutationProof() *merklehasher.Proof[Identifier] {
-	// We can ignore the error because Identifier.Bytes() will never return an error
-	//nolint:nosnakecase // false positive
-	return lo.PanicOnErr(merklehasher.NewHasher[Identifier](crypto.BLAKE2b_256).ComputeProofForIndex(r.values(), 1))
+	return lo.PanicOnErr(r.ProofFor(RootsFieldStateMutation))
+}
+
+// StateProof returns the proof of the StateRoot in the merkle tree of the Roots.
+func (r *Roots) StateProof() *merklehasher.Proof[Identifier] {
+	return lo.PanicOnErr(r.ProofFor(RootsFieldState))
+}
+
+// AccountProof returns the proof of the AccountRoot in the merkle tree of the Roots.
+func (r *Roots) AccountProof() *merklehasher.Proof[Identifier] {
+	return lo.PanicOnErr(r.ProofFor(RootsFieldAccount))
+}
+
+// CommitteeProof returns the proof of the CommitteeRoot in the merkle tree of the Roots.
+func (r *Roots) CommitteeProof() *merklehasher.Proof[Identifier] {
+	return lo.PanicOnErr(r.ProofFor(RootsFieldCommittee))
+}
+
+// RewardsProof returns the proof of the RewardsRoot in the merkle tree of the Roots.
+func (r *Roots) RewardsProof() *merklehasher.Proof[Identifier] {
+	return lo.PanicOnErr(r.ProofFor(RootsFieldRewards))
+}
+
+// ProtocolParametersHashProof returns the proof of the ProtocolParametersHash in the merkle tree of the Roots.
+func (r *Roots) ProtocolParametersHashProof() *merklehasher.Proof[Identifier] {
+	return lo.PanicOnErr(r.ProofFor(RootsFieldProtocolParametersHash))
 }
 
 func VerifyProof(proof *merklehasher.Proof[Identifier], proofedRoot Identifier, treeRoot Identifier) bool {
@@ -84,6 +177,55 @@ func VerifyProof(proof *merklehasher.Proof[Identifier], proofedRoot Identifier,
 	return treeRoot == Identifier(proof.Hash(merklehasher.NewHasher[Identifier](crypto.BLAKE2b_256)))
 }
 
+// VerifyRootsProof verifies that the proof proves the given value at the position of the field in the Roots with the given ID.
+// Unlike VerifyProof, it rejects proofs of the value at the position of another field.
+func VerifyRootsProof(proof *merklehasher.Proof[Identifier], field RootsField, proofedValue Identifier, rootsID Identifier) bool {
+	if proof == nil || int(field) >= rootsFieldCount {
+		return false
+	}
+
+	if index, ok := proofValueIndex(proof.MerkleHashable, rootsFieldCount); !ok || index != int(field) {
+		return false
+	}
+
+	return VerifyProof(proof, proofedValue, rootsID)
+}
+
+// VerifyCommitmentRootsProof verifies that the proof proves the given value at the position of the field in the Roots of the commitment.
+func VerifyCommitmentRootsProof(proof *merklehasher.Proof[Identifier], field RootsField, proofedValue Identifier, commitment *Commitment) bool {
+	return VerifyRootsProof(proof, field, proofedValue, commitment.RootsID)
+}
+
+// proofValueIndex returns the index of the proofed value in a merkle tree with the given amount of leaves.
+// The leaves of the Roots form a perfect binary tree, so every node splits its leaves in half.
+func proofValueIndex(hashable merklehasher.MerkleHashable[Identifier], leaves int) (int, bool) {
+	switch node := hashable.(type) {
+	case *merklehasher.ValueHash[Identifier]:
+		return 0, leaves == 1
+
+	case *merklehasher.Node[Identifier]:
+		if leaves < 2 {
+			return 0, false
+		}
+
+		half := leaves / 2
+		if _, isLeafHash := node.Right.(*merklehasher.LeafHash[Identifier]); isLeafHash {
+			return proofValueIndex(node.Left, half)
+		}
+
+		if _, isLeafHash := node.Left.(*merklehasher.LeafHash[Identifier]); isLeafHash {
+			index, ok := proofValueIndex(node.Right, leaves-half)
+
+			return half + index, ok
+		}
+
+		return 0, false
+
+	default:
+		return 0, false
+	}
+}
+
 func (r *Roots) String() string {
 	return fmt.Sprintf(
 		"Roots(%s): TangleRoot: %s, StateMutationRoot: %s, StateRoot: %s, AccountRoot: %s, AttestationsRoot: %s, CommitteeRoot: %s, RewardsRoot: %s, ProtocolParametersHash: %s", r.ID(), r.TangleRoot, r.StateMutationRoot, r.StateRoot, r.AccountRoot, r.AttestationsRoot, r.CommitteeRoot, r.RewardsRoot, r.ProtocolParametersHash)
@@ -0,0 +1,111 @@
+package iotago_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/merklehasher"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+// referenceRootsID computes the ID of the Roots as the root of a perfect merkle tree of their fields.
+func referenceRootsID(values []iotago.Identifier) iotago.Identifier {
+	hashes := make([][]byte, len(values))
+	for i, value := range values {
+		leafHash := blake2b.Sum256(append([]byte{0}, value[:]...))
+		hashes[i] = leafHash[:]
+	}
+
+	for len(hashes) > 1 {
+		nodes := make([][]byte, len(hashes)/2)
+		for i := range nodes {
+			nodeHash := blake2b.Sum256(append(append([]byte{1}, hashes[2*i]...), hashes[2*i+1]...))
+			nodes[i] = nodeHash[:]
+		}
+		hashes = nodes
+	}
+
+	return iotago.Identifier(hashes[0])
+}
+
+func TestRoots_ProofFor(t *testing.T) {
+	roots := iotago.NewRoots(tpkg.RandIdentifier(), tpkg.RandIdentifier(), tpkg.RandIdentifier(), tpkg.RandIdentifier(), tpkg.RandIdentifier(), tpkg.RandIdentifier(), tpkg.RandIdentifier(), tpkg.RandIdentifier())
+
+	values := []iotago.Identifier{roots.TangleRoot, roots.StateMutationRoot, roots.StateRoot, roots.AccountRoot, roots.AttestationsRoot, roots.CommitteeRoot, roots.RewardsRoot, roots.ProtocolParametersHash}
+	rootsID := referenceRootsID(values)
+	require.Equal(t, rootsID, roots.ID())
+
+	fields := []iotago.RootsField{
+		iotago.RootsFieldTangle,
+		iotago.RootsFieldStateMutation,
+		iotago.RootsFieldState,
+		iotago.RootsFieldAccount,
+		iotago.RootsFieldAttestations,
+		iotago.RootsFieldCommittee,
+		iotago.RootsFieldRewards,
+		iotago.RootsFieldProtocolParametersHash,
+	}
+
+	for i, field := range fields {
+		t.Run(field.String(), func(t *testing.T) {
+			value, err := roots.Get(field)
+			require.NoError(t, err)
+			require.Equal(t, values[i], value)
+
+			proof, err := roots.ProofFor(field)
+			require.NoError(t, err)
+
+			require.True(t, iotago.VerifyProof(proof, value, rootsID))
+			require.True(t, iotago.VerifyRootsProof(proof, field, value, rootsID))
+			require.False(t, iotago.VerifyRootsProof(proof, field, tpkg.RandIdentifier(), rootsID))
+			require.False(t, iotago.VerifyRootsProof(proof, field, value, tpkg.RandIdentifier()))
+
+			// the proof only proves the value at the position of the field
+			for _, otherField := range fields {
+				if otherField != field {
+					require.False(t, iotago.VerifyRootsProof(proof, otherField, value, rootsID))
+				}
+			}
+
+			serializedProof, err := tpkg.ZeroCostTestAPI.Encode(proof)
+			require.NoError(t, err)
+
+			deserializedProof := new(merklehasher.Proof[iotago.Identifier])
+			_, err = tpkg.ZeroCostTestAPI.Decode(serializedProof, deserializedProof)
+			require.NoError(t, err)
+			require.True(t, iotago.VerifyRootsProof(deserializedProof, field, value, rootsID))
+		})
+	}
+
+	require.Equal(t, roots.TangleProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldTangle)))
+	require.Equal(t, roots.MutationProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldStateMutation)))
+	require.Equal(t, roots.StateProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldState)))
+	require.Equal(t, roots.AccountProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldAccount)))
+	require.Equal(t, roots.AttestationsProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldAttestations)))
+	require.Equal(t, roots.CommitteeProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldCommittee)))
+	require.Equal(t, roots.RewardsProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldRewards)))
+	require.Equal(t, roots.ProtocolParametersHashProof(), lo.PanicOnErr(roots.ProofFor(iotago.RootsFieldProtocolParametersHash)))
+
+	t.Run("err - unknown field", func(t *testing.T) {
+		unknownField := iotago.RootsFieldProtocolParametersHash + 1
+
+		_, err := roots.ProofFor(unknownField)
+		require.ErrorIs(t, err, iotago.ErrUnknownRootsField)
+
+		_, err = roots.Get(unknownField)
+		require.ErrorIs(t, err, iotago.ErrUnknownRootsField)
+
+		require.False(t, iotago.VerifyRootsProof(roots.TangleProof(), unknownField, roots.TangleRoot, rootsID))
+	})
+
+	t.Run("ok - commitment", func(t *testing.T) {
+		commitment := iotago.NewCommitment(tpkg.ZeroCostTestAPI.Version(), tpkg.RandSlot(), tpkg.RandCommitmentID(), roots.ID(), 100, 1)
+
+		require.True(t, iotago.VerifyCommitmentRootsProof(roots.CommitteeProof(), iotago.RootsFieldCommittee, roots.CommitteeRoot, commitment))
+		require.False(t, iotago.VerifyCommitmentRootsProof(roots.CommitteeProof(), iotago.RootsFieldRewards, roots.CommitteeRoot, commitment))
+	})
+}
@@ -0,0 +1,156 @@
+package lightclient
+
+import (
+	"sync"
+
+	hiveEd25519 "github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/ierrors"
+	iotago "github.com/iotaledger/iota.go/v4"
+)
+
+var (
+	// ErrCommitmentChainBroken gets returned when a commitment doesn't reference the previous commitment of the chain.
+	ErrCommitmentChainBroken = ierrors.New("commitment does not reference the previous commitment")
+	// ErrCommitmentSlotNotConsecutive gets returned when the slot of a commitment doesn't directly follow the slot of the previous commitment.
+	ErrCommitmentSlotNotConsecutive = ierrors.New("commitment slot does not follow the previous slot")
+	// ErrCommitmentCumulativeWeightDecreasing gets returned when the cumulative weight of a commitment is lower than the one of the previous commitment.
+	ErrCommitmentCumulativeWeightDecreasing = ierrors.New("commitment cumulative weight is decreasing")
+	// ErrCommitteeEmpty gets returned when attestations are verified against an empty committee.
+	ErrCommitteeEmpty = ierrors.New("committee is empty")
+	// ErrAttestationSupermajorityNotReached gets returned when less than a supermajority of the committee attested a commitment.
+	ErrAttestationSupermajorityNotReached = ierrors.New("attestations do not reach a supermajority of the committee")
+)
+
+// Committee holds the block issuer keys of the members of the committee of an epoch.
+type Committee map[iotago.AccountID]iotago.BlockIssuerKeys
+
+// CommitmentChain verifies commitments received from an untrusted node,
+// starting from a commitment which is trusted, e.g. because it is part of a snapshot.
+type CommitmentChain struct {
+	latestCommitment   *iotago.Commitment
+	latestCommitmentID iotago.CommitmentID
+	mutex              sync.RWMutex
+}
+
+// NewCommitmentChain returns a new CommitmentChain which starts at the given trusted commitment.
+func NewCommitmentChain(trustedCommitment *iotago.Commitment) (*CommitmentChain, error) {
+	trustedCommitmentID, err := trustedCommitment.ID()
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to compute the ID of the trusted commitment")
+	}
+
+	return &CommitmentChain{
+		latestCommitment:   trustedCommitment,
+		latestCommitmentID: trustedCommitmentID,
+	}, nil
+}
+
+// Latest returns the latest verified commitment of the chain and its ID.
+func (c *CommitmentChain) Latest() (*iotago.Commitment, iotago.CommitmentID) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.latestCommitment, c.latestCommitmentID
+}
+
+// Extend verifies that the given commitments of consecutive slots extend the chain and appends them.
+// Every commitment must reference the ID of its predecessor, have the slot following the slot of its predecessor
+// and at least the same cumulative weight.
+// The chain is only extended if all commitments are valid.
+func (c *CommitmentChain) Extend(commitments ...*iotago.Commitment) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	previousCommitment, previousCommitmentID := c.latestCommitment, c.latestCommitmentID
+	for _, commitment := range commitments {
+		commitmentID, err := verifyCommitmentLink(previousCommitment, previousCommitmentID, commitment)
+		if err != nil {
+			return err
+		}
+
+		previousCommitment, previousCommitmentID = commitment, commitmentID
+	}
+
+	c.latestCommitment, c.latestCommitmentID = previousCommitment, previousCommitmentID
+
+	return nil
+}
+
+// verifyCommitmentLink verifies that the commitment follows the previous commitment and returns the ID of the commitment.
+func verifyCommitmentLink(previousCommitment *iotago.Commitment, previousCommitmentID iotago.CommitmentID, commitment *iotago.Commitment) (iotago.CommitmentID, error) {
+	if commitment.PreviousCommitmentID != previousCommitmentID {
+		return iotago.EmptyCommitmentID, ierrors.Wrapf(ErrCommitmentChainBroken, "commitment of slot %d references %s instead of %s", commitment.Slot, commitment.PreviousCommitmentID, previousCommitmentID)
+	}
+
+	// every slot is committed, so a gap means the chain is not complete
+	if commitment.Slot != previousCommitment.Slot+1 {
+		return iotago.EmptyCommitmentID, ierrors.Wrapf(ErrCommitmentSlotNotConsecutive, "slot %d follows slot %d", commitment.Slot, previousCommitment.Slot)
+	}
+
+	if commitment.CumulativeWeight < previousCommitment.CumulativeWeight {
+		return iotago.EmptyCommitmentID, ierrors.Wrapf(ErrCommitmentCumulativeWeightDecreasing, "cumulative weight %d of slot %d is lower than %d of slot %d", commitment.CumulativeWeight, commitment.Slot, previousCommitment.CumulativeWeight, previousCommitment.Slot)
+	}
+
+	commitmentID, err := commitment.ID()
+	if err != nil {
+		return iotago.EmptyCommitmentID, ierrors.Wrapf(err, "failed to compute the ID of the commitment of slot %d", commitment.Slot)
+	}
+
+	return commitmentID, nil
+}
+
+// VerifyAttestations verifies that more than two thirds of the seats of the committee attested the given commitment.
+// An attestation only counts if it was issued by a committee member, references the commitment
+// and is signed with one of the block issuer keys of the member. Every member is counted once.
+func VerifyAttestations(commitmentID iotago.CommitmentID, committee Committee, attestations iotago.Attestations) error {
+	if len(committee) == 0 {
+		return ErrCommitteeEmpty
+	}
+
+	attestingSeats := make(map[iotago.AccountID]struct{})
+	for _, attestation := range attestations {
+		if attestation.Header.SlotCommitmentID != commitmentID {
+			continue
+		}
+
+		if _, alreadyCounted := attestingSeats[attestation.Header.IssuerID]; alreadyCounted {
+			continue
+		}
+
+		blockIssuerKeys, isMember := committee[attestation.Header.IssuerID]
+		if !isMember || !signedByBlockIssuerKey(attestation, blockIssuerKeys) {
+			continue
+		}
+
+		attestingSeats[attestation.Header.IssuerID] = struct{}{}
+	}
+
+	if !isSupermajority(len(attestingSeats), len(committee)) {
+		return ierrors.Wrapf(ErrAttestationSupermajorityNotReached, "%d of %d seats attested commitment %s", len(attestingSeats), len(committee), commitmentID)
+	}
+
+	return nil
+}
+
+// signedByBlockIssuerKey returns whether the attestation has a valid signature of one of the given block issuer keys.
+func signedByBlockIssuerKey(attestation *iotago.Attestation, blockIssuerKeys iotago.BlockIssuerKeys) bool {
+	signature, isEd25519Signature := attestation.Signature.(*iotago.Ed25519Signature)
+	if !isEd25519Signature {
+		return false
+	}
+
+	publicKey := hiveEd25519.PublicKey(signature.PublicKey)
+	if !blockIssuerKeys.Has(iotago.Ed25519PublicKeyBlockIssuerKeyFromPublicKey(publicKey)) &&
+		!blockIssuerKeys.Has(iotago.Ed25519PublicKeyHashBlockIssuerKeyFromPublicKey(signature.PublicKey[:])) {
+		return false
+	}
+
+	valid, err := attestation.VerifySignature()
+
+	return err == nil && valid
+}
+
+// isSupermajority returns whether the given amount of seats is more than two thirds of the committee.
+func isSupermajority(seats int, committeeSize int) bool {
+	return seats*3 > committeeSize*2
+}
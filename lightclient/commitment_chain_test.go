@@ -0,0 +1,145 @@
+package lightclient_test
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	hiveEd25519 "github.com/iotaledger/hive.go/crypto/ed25519"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/builder"
+	"github.com/iotaledger/iota.go/v4/lightclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+func nextCommitment(previous *iotago.Commitment, slotDelta iotago.SlotIndex, weightDelta uint64) *iotago.Commitment {
+	return iotago.NewCommitment(previous.ProtocolVersion, previous.Slot+slotDelta, previous.MustID(), tpkg.Rand32ByteArray(), previous.CumulativeWeight+weightDelta, previous.ReferenceManaCost)
+}
+
+func TestCommitmentChain(t *testing.T) {
+	trusted := iotago.NewCommitment(tpkg.ZeroCostTestAPI.Version(), 10, tpkg.RandCommitmentID(), tpkg.Rand32ByteArray(), 100, 1)
+
+	chain, err := lightclient.NewCommitmentChain(trusted)
+	require.NoError(t, err)
+
+	commitment11 := nextCommitment(trusted, 1, 5)
+	commitment12 := nextCommitment(commitment11, 1, 0)
+	require.NoError(t, chain.Extend(commitment11, commitment12))
+
+	latest, latestID := chain.Latest()
+	require.Equal(t, commitment12, latest)
+	require.Equal(t, commitment12.MustID(), latestID)
+
+	t.Run("err - broken link", func(t *testing.T) {
+		commitment := nextCommitment(commitment12, 1, 1)
+		commitment.PreviousCommitmentID = commitment11.MustID()
+
+		require.ErrorIs(t, chain.Extend(commitment), lightclient.ErrCommitmentChainBroken)
+	})
+
+	t.Run("err - slot not increasing", func(t *testing.T) {
+		require.ErrorIs(t, chain.Extend(nextCommitment(commitment12, 0, 1)), lightclient.ErrCommitmentSlotNotConsecutive)
+	})
+
+	t.Run("err - slot gap", func(t *testing.T) {
+		require.ErrorIs(t, chain.Extend(nextCommitment(commitment12, 2, 1)), lightclient.ErrCommitmentSlotNotConsecutive)
+	})
+
+	t.Run("err - cumulative weight decreasing", func(t *testing.T) {
+		commitment := nextCommitment(commitment12, 1, 0)
+		commitment.CumulativeWeight--
+
+		require.ErrorIs(t, chain.Extend(commitment), lightclient.ErrCommitmentCumulativeWeightDecreasing)
+	})
+
+	t.Run("err - chain is not extended partially", func(t *testing.T) {
+		commitment13 := nextCommitment(commitment12, 1, 1)
+		require.ErrorIs(t, chain.Extend(commitment13, nextCommitment(commitment12, 2, 1)), lightclient.ErrCommitmentChainBroken)
+
+		_, latestID := chain.Latest()
+		require.Equal(t, commitment12.MustID(), latestID)
+	})
+}
+
+type committeeMember struct {
+	accountID  iotago.AccountID
+	privateKey ed25519.PrivateKey
+}
+
+func (m *committeeMember) attest(t *testing.T, commitmentID iotago.CommitmentID) *iotago.Attestation {
+	t.Helper()
+
+	block, err := builder.NewValidationBlockBuilder(tpkg.ZeroCostTestAPI).
+		StrongParents(tpkg.SortedRandBlockIDs(1)).
+		SlotCommitmentID(commitmentID).
+		Sign(m.accountID, m.privateKey).
+		Build()
+	require.NoError(t, err)
+
+	return iotago.NewAttestation(tpkg.ZeroCostTestAPI, block)
+}
+
+func TestVerifyAttestations(t *testing.T) {
+	commitmentID := tpkg.RandCommitmentID()
+
+	members := make([]*committeeMember, 4)
+	committee := make(lightclient.Committee)
+	for i := range members {
+		members[i] = &committeeMember{accountID: tpkg.RandAccountID(), privateKey: tpkg.RandEd25519PrivateKey()}
+
+		publicKey := hiveEd25519.PublicKey(members[i].privateKey.Public().(ed25519.PublicKey))
+		if i%2 == 0 {
+			committee[members[i].accountID] = iotago.NewBlockIssuerKeys(iotago.Ed25519PublicKeyBlockIssuerKeyFromPublicKey(publicKey))
+		} else {
+			committee[members[i].accountID] = iotago.NewBlockIssuerKeys(iotago.Ed25519PublicKeyHashBlockIssuerKeyFromPublicKey(publicKey[:]))
+		}
+	}
+
+	t.Run("ok - supermajority", func(t *testing.T) {
+		attestations := iotago.Attestations{
+			members[0].attest(t, commitmentID),
+			members[1].attest(t, commitmentID),
+			members[2].attest(t, commitmentID),
+		}
+
+		require.NoError(t, lightclient.VerifyAttestations(commitmentID, committee, attestations))
+	})
+
+	t.Run("err - two thirds is not a supermajority", func(t *testing.T) {
+		attestations := iotago.Attestations{
+			members[0].attest(t, commitmentID),
+			members[1].attest(t, commitmentID),
+			// members are only counted once
+			members[1].attest(t, commitmentID),
+			// attestations of other commitments are ignored
+			members[2].attest(t, tpkg.RandCommitmentID()),
+		}
+
+		require.ErrorIs(t, lightclient.VerifyAttestations(commitmentID, committee, attestations), lightclient.ErrAttestationSupermajorityNotReached)
+	})
+
+	t.Run("err - invalid attestations", func(t *testing.T) {
+		// signed with a key which is not a block issuer key of the member
+		wrongKey := &committeeMember{accountID: members[2].accountID, privateKey: tpkg.RandEd25519PrivateKey()}
+		// not a committee member
+		outsider := &committeeMember{accountID: tpkg.RandAccountID(), privateKey: tpkg.RandEd25519PrivateKey()}
+		// the signature doesn't match the attestation anymore
+		tampered := members[3].attest(t, commitmentID)
+		tampered.Header.IssuingTime = tampered.Header.IssuingTime.Add(1)
+
+		attestations := iotago.Attestations{
+			members[0].attest(t, commitmentID),
+			members[1].attest(t, commitmentID),
+			wrongKey.attest(t, commitmentID),
+			outsider.attest(t, commitmentID),
+			tampered,
+		}
+
+		require.ErrorIs(t, lightclient.VerifyAttestations(commitmentID, committee, attestations), lightclient.ErrAttestationSupermajorityNotReached)
+	})
+
+	t.Run("err - empty committee", func(t *testing.T) {
+		require.ErrorIs(t, lightclient.VerifyAttestations(commitmentID, lightclient.Committee{}, nil), lightclient.ErrCommitteeEmpty)
+	})
+}
@@ -0,0 +1,78 @@
+package merklehasher
+
+import (
+	"crypto"
+)
+
+// IncrementalHasher computes the Merkle tree hash of values which are added one by one.
+// It only keeps the roots of the perfect subtrees of the values added so far, so the root
+// can be computed at any time without keeping all values in memory.
+// The IncrementalHasher is not safe for concurrent use.
+type IncrementalHasher[V Value] struct {
+	hasher *Hasher[V]
+	// subtreeRoots contains the roots of the perfect subtrees, ordered from the largest to the smallest.
+	// There is one subtree for every bit which is set in count.
+	subtreeRoots [][]byte
+	count        uint64
+}
+
+// NewIncrementalHasher creates a new IncrementalHasher using the provided hash function.
+func NewIncrementalHasher[V Value](h crypto.Hash) *IncrementalHasher[V] {
+	return &IncrementalHasher[V]{
+		hasher: NewHasher[V](h),
+	}
+}
+
+// Add adds the value as the next leaf of the tree.
+func (t *IncrementalHasher[V]) Add(value V) error {
+	valueBytes, err := value.Bytes()
+	if err != nil {
+		return err
+	}
+
+	t.AddBytes(valueBytes)
+
+	return nil
+}
+
+// AddBytes adds the data as the next leaf of the tree.
+func (t *IncrementalHasher[V]) AddBytes(data []byte) {
+	hash := t.hasher.hashLeaf(data)
+
+	// merge the subtrees of equal size, like a carry when incrementing the count
+	for count := t.count; count&1 == 1; count >>= 1 {
+		last := len(t.subtreeRoots) - 1
+		hash = t.hasher.hashNode(t.subtreeRoots[last], hash)
+		t.subtreeRoots = t.subtreeRoots[:last]
+	}
+
+	t.subtreeRoots = append(t.subtreeRoots, hash)
+	t.count++
+}
+
+// Count returns the amount of leaves added so far.
+func (t *IncrementalHasher[V]) Count() uint64 {
+	return t.count
+}
+
+// Root returns the Merkle tree hash of the leaves added so far.
+// It is equal to the hash computed by Hasher.Hash for the same leaves.
+func (t *IncrementalHasher[V]) Root() []byte {
+	if len(t.subtreeRoots) == 0 {
+		return t.hasher.emptyLeaf()
+	}
+
+	// the left subtree of every node is the largest perfect subtree, so the subtrees are merged from the right
+	root := t.subtreeRoots[len(t.subtreeRoots)-1]
+	for i := len(t.subtreeRoots) - 2; i >= 0; i-- {
+		root = t.hasher.hashNode(t.subtreeRoots[i], root)
+	}
+
+	return root
+}
+
+// Reset removes all leaves from the tree.
+func (t *IncrementalHasher[V]) Reset() {
+	t.subtreeRoots = nil
+	t.count = 0
+}
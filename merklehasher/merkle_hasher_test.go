@@ -4,6 +4,9 @@ package merklehasher_test
 import (
 	"bytes"
 	"crypto"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -62,3 +65,257 @@ func TestMerkleHasher(t *testing.T) {
 		require.True(t, bytes.Equal(hash, pathFromJSON.Hash(hasher)))
 	}
 }
+
+func countLeafHashes(hashable merklehasher.MerkleHashable[iotago.BlockID]) int {
+	switch node := hashable.(type) {
+	case *merklehasher.LeafHash[iotago.BlockID]:
+		return 1
+	case *merklehasher.Node[iotago.BlockID]:
+		return countLeafHashes(node.Left) + countLeafHashes(node.Right)
+	default:
+		return 0
+	}
+}
+
+func TestMerkleHasher_MultiProof(t *testing.T) {
+	includedBlocks := make(iotago.BlockIDs, 7)
+	for i := range includedBlocks {
+		includedBlocks[i][0] = byte(i + 1)
+	}
+
+	//nolint:nosnakecase // false positive
+	hasher := merklehasher.NewHasher[iotago.BlockID](crypto.BLAKE2b_256)
+	hash, err := hasher.HashValues(includedBlocks)
+	require.NoError(t, err)
+
+	// prove every non-empty subset of the blocks
+	for subset := 1; subset < 1<<len(includedBlocks); subset++ {
+		var indices []int
+		var values iotago.BlockIDs
+		singleProofHashes := 0
+		for i := range includedBlocks {
+			if subset&(1<<i) != 0 {
+				indices = append(indices, i)
+				values = append(values, includedBlocks[i])
+
+				singleProof, err := hasher.ComputeProofForIndex(includedBlocks, i)
+				require.NoError(t, err)
+				singleProofHashes += countLeafHashes(singleProof.MerkleHashable)
+			}
+		}
+
+		proof, err := hasher.ComputeMultiProofForIndices(includedBlocks, indices)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(proof.Hashes), singleProofHashes)
+
+		valid, err := proof.Verify(values, hash, hasher)
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		proofByValues, provenValues, err := hasher.ComputeMultiProof(includedBlocks, values)
+		require.NoError(t, err)
+		require.Equal(t, proof, proofByValues)
+		require.Equal(t, values, iotago.BlockIDs(provenValues))
+
+		proofBytes, err := proof.Bytes()
+		require.NoError(t, err)
+
+		proofFromBytes, consumedBytes, err := merklehasher.MultiProofFromBytes[iotago.BlockID](proofBytes)
+		require.NoError(t, err)
+		require.Equal(t, len(proofBytes), consumedBytes)
+		require.Equal(t, proof, proofFromBytes)
+
+		jsonProof, err := proof.JSONEncode()
+		require.NoError(t, err)
+
+		proofFromJSON, err := merklehasher.MultiProofFromJSON[iotago.BlockID](jsonProof)
+		require.NoError(t, err)
+		require.Equal(t, proof, proofFromJSON)
+	}
+
+	t.Run("ok - shared hashes are contained once", func(t *testing.T) {
+		proof, err := hasher.ComputeMultiProofForIndices(includedBlocks, []int{0, 1, 2, 3})
+		require.NoError(t, err)
+		require.Len(t, proof.Hashes, 1)
+
+		proof, err = hasher.ComputeMultiProofForIndices(includedBlocks, []int{0, 1, 2, 3, 4, 5, 6})
+		require.NoError(t, err)
+		require.Empty(t, proof.Hashes)
+	})
+
+	t.Run("ok - indices are sorted", func(t *testing.T) {
+		proof, err := hasher.ComputeMultiProofForIndices(includedBlocks, []int{5, 1})
+		require.NoError(t, err)
+		require.Equal(t, []uint32{1, 5}, proof.Indices)
+
+		valid, err := proof.Verify(iotago.BlockIDs{includedBlocks[1], includedBlocks[5]}, hash, hasher)
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		// the values must be given in the order of the indices
+		valid, err = proof.Verify(iotago.BlockIDs{includedBlocks[5], includedBlocks[1]}, hash, hasher)
+		require.NoError(t, err)
+		require.False(t, valid)
+	})
+
+	t.Run("ok - values are returned in the order of the indices", func(t *testing.T) {
+		proof, provenValues, err := hasher.ComputeMultiProof(includedBlocks, iotago.BlockIDs{includedBlocks[5], includedBlocks[1]})
+		require.NoError(t, err)
+		require.Equal(t, []uint32{1, 5}, proof.Indices)
+		require.Equal(t, iotago.BlockIDs{includedBlocks[1], includedBlocks[5]}, iotago.BlockIDs(provenValues))
+
+		valid, err := proof.Verify(provenValues, hash, hasher)
+		require.NoError(t, err)
+		require.True(t, valid)
+	})
+
+	t.Run("ok - duplicate values", func(t *testing.T) {
+		duplicateBlocks := iotago.BlockIDs{includedBlocks[0], includedBlocks[1], includedBlocks[0], includedBlocks[2]}
+		duplicateHash, err := hasher.HashValues(duplicateBlocks)
+		require.NoError(t, err)
+
+		proof, provenValues, err := hasher.ComputeMultiProof(duplicateBlocks, iotago.BlockIDs{includedBlocks[0], includedBlocks[2], includedBlocks[0]})
+		require.NoError(t, err)
+		require.Equal(t, []uint32{0, 2, 3}, proof.Indices)
+
+		valid, err := proof.Verify(provenValues, duplicateHash, hasher)
+		require.NoError(t, err)
+		require.True(t, valid)
+	})
+
+	t.Run("err - invalid indices", func(t *testing.T) {
+		_, err := hasher.ComputeMultiProofForIndices(includedBlocks, nil)
+		require.Error(t, err)
+
+		_, err = hasher.ComputeMultiProofForIndices(includedBlocks, []int{1, 7})
+		require.Error(t, err)
+
+		_, err = hasher.ComputeMultiProofForIndices(includedBlocks, []int{1, 1})
+		require.Error(t, err)
+
+		_, _, err = hasher.ComputeMultiProof(includedBlocks, iotago.BlockIDs{iotago.EmptyBlockID})
+		require.Error(t, err)
+
+		// every occurrence of a value can only be proven once
+		_, _, err = hasher.ComputeMultiProof(includedBlocks, iotago.BlockIDs{includedBlocks[3], includedBlocks[3]})
+		require.Error(t, err)
+	})
+
+	t.Run("err - malformed proof", func(t *testing.T) {
+		values := iotago.BlockIDs{includedBlocks[2], includedBlocks[6]}
+
+		proof, err := hasher.ComputeMultiProofForIndices(includedBlocks, []int{2, 6})
+		require.NoError(t, err)
+
+		_, err = proof.Verify(values[:1], hash, hasher)
+		require.Error(t, err)
+
+		missingHash := *proof
+		missingHash.Hashes = proof.Hashes[1:]
+		_, err = missingHash.Verify(values, hash, hasher)
+		require.Error(t, err)
+
+		unusedHash := *proof
+		unusedHash.Hashes = append(append([]merklehasher.MultiProofHash[iotago.BlockID](nil), proof.Hashes...), proof.Hashes[0])
+		_, err = unusedHash.Verify(values, hash, hasher)
+		require.Error(t, err)
+
+		unsortedIndices := *proof
+		unsortedIndices.Indices = []uint32{6, 2}
+		_, err = unsortedIndices.Verify(values, hash, hasher)
+		require.Error(t, err)
+
+		outOfBounds := *proof
+		outOfBounds.Indices = []uint32{2, 7}
+		_, err = outOfBounds.Verify(values, hash, hasher)
+		require.Error(t, err)
+	})
+}
+
+func TestMerkleHasher_Incremental(t *testing.T) {
+	//nolint:nosnakecase // false positive
+	hasher := merklehasher.NewHasher[iotago.BlockID](crypto.BLAKE2b_256)
+	//nolint:nosnakecase // false positive
+	incrementalHasher := merklehasher.NewIncrementalHasher[iotago.BlockID](crypto.BLAKE2b_256)
+
+	require.Equal(t, hasher.EmptyRoot(), incrementalHasher.Root())
+
+	var includedBlocks iotago.BlockIDs
+	for i := 0; i < 130; i++ {
+		var blockID iotago.BlockID
+		binary.LittleEndian.PutUint32(blockID[:], uint32(i))
+
+		includedBlocks = append(includedBlocks, blockID)
+		require.NoError(t, incrementalHasher.Add(blockID))
+		require.EqualValues(t, len(includedBlocks), incrementalHasher.Count())
+
+		hash, err := hasher.HashValues(includedBlocks)
+		require.NoError(t, err)
+		require.Equal(t, hash, incrementalHasher.Root())
+	}
+
+	incrementalHasher.Reset()
+	require.Zero(t, incrementalHasher.Count())
+	require.Equal(t, hasher.EmptyRoot(), incrementalHasher.Root())
+}
+
+func TestMerkleHasher_Parallel(t *testing.T) {
+	//nolint:nosnakecase // false positive
+	hasher := merklehasher.NewHasher[iotago.BlockID](crypto.BLAKE2b_256)
+
+	for _, count := range []int{0, 1, 2, 2047, 2048, 2049, 10000} {
+		includedBlocks := randBlockIDs(count)
+
+		hash, err := hasher.HashValues(includedBlocks)
+		require.NoError(t, err)
+
+		for _, parallelism := range []int{0, 1, 2, 3, 16} {
+			parallelHash, err := hasher.HashValuesParallel(includedBlocks, parallelism)
+			require.NoError(t, err)
+			require.Equal(t, hash, parallelHash, "count=%d parallelism=%d", count, parallelism)
+		}
+	}
+}
+
+func randBlockIDs(count int) iotago.BlockIDs {
+	blockIDs := make(iotago.BlockIDs, count)
+	for i := range blockIDs {
+		//nolint:gosec // we don't need crypto random numbers for tests
+		_, _ = rand.Read(blockIDs[i][:])
+	}
+
+	return blockIDs
+}
+
+func BenchmarkMerkleHasher(b *testing.B) {
+	//nolint:nosnakecase // false positive
+	hasher := merklehasher.NewHasher[iotago.BlockID](crypto.BLAKE2b_256)
+
+	for _, count := range []int{1_000, 100_000} {
+		includedBlocks := randBlockIDs(count)
+
+		b.Run(fmt.Sprintf("HashValues/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = hasher.HashValues(includedBlocks)
+			}
+		})
+
+		// the parallel mode only differs from HashValues with multiple CPUs, e.g. run with -cpu 1,4
+		b.Run(fmt.Sprintf("HashValuesParallel/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = hasher.HashValuesParallel(includedBlocks, 0)
+			}
+		})
+
+		b.Run(fmt.Sprintf("Incremental/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				//nolint:nosnakecase // false positive
+				incrementalHasher := merklehasher.NewIncrementalHasher[iotago.BlockID](crypto.BLAKE2b_256)
+				for _, blockID := range includedBlocks {
+					_ = incrementalHasher.Add(blockID)
+				}
+				_ = incrementalHasher.Root()
+			}
+		})
+	}
+}
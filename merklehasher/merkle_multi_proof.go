@@ -0,0 +1,265 @@
+package merklehasher
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/iota.go/v4/hexutil"
+)
+
+// MultiProofHash contains the hash of a subtree which contains none of the values proven by a MultiProof.
+type MultiProofHash[V Value] []byte
+
+// MultiProof proves the inclusion of the values at multiple indices of the tree.
+// Every hash is only contained once, so it is smaller than the single proofs of all values together.
+type MultiProof[V Value] struct {
+	// The amount of leaves of the tree.
+	LeafCount uint32 `serix:""`
+	// The indices of the proven values in ascending order.
+	Indices []uint32 `serix:",lenPrefix=uint32"`
+	// The hashes of the subtrees which contain none of the proven values, in the order they are needed to compute the root.
+	Hashes []MultiProofHash[V] `serix:",lenPrefix=uint32"`
+}
+
+// ComputeMultiProof computes the proof given the values and the values we want to create the inclusion proof for.
+// It returns the values to proof in the order of the indices of the proof, which is the order Verify expects them in.
+// A value which is contained multiple times in the values can be proven once for each of its occurrences.
+func (t *Hasher[V]) ComputeMultiProof(values []V, valuesToProof []V) (*MultiProof[V], []V, error) {
+	data, err := valuesBytes(values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usedIndices := make(map[int]struct{}, len(valuesToProof))
+	indexedValues := make(map[int]V, len(valuesToProof))
+	indices := make([]int, len(valuesToProof))
+	for i := range valuesToProof {
+		valueToProofBytes, err := valuesToProof[i].Bytes()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// duplicate values are matched with the next occurrence which is not proven yet
+		index := -1
+		for j := range data {
+			if _, used := usedIndices[j]; !used && bytes.Equal(valueToProofBytes, data[j]) {
+				index = j
+
+				break
+			}
+		}
+		if index == -1 {
+			return nil, nil, ierrors.Errorf("value %s is not contained in the given list", hexutil.EncodeHex(valueToProofBytes))
+		}
+
+		usedIndices[index] = struct{}{}
+		indexedValues[index] = valuesToProof[i]
+		indices[i] = index
+	}
+
+	proof, err := t.computeMultiProof(data, indices)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provenValues := make([]V, len(proof.Indices))
+	for i, index := range proof.Indices {
+		provenValues[i] = indexedValues[int(index)]
+	}
+
+	return proof, provenValues, nil
+}
+
+// ComputeMultiProofForIndices computes the proof given the values and the indices of the values we want to create the inclusion proof for.
+// The indices of the proof are sorted, so Verify expects the proven values in ascending order of their indices.
+func (t *Hasher[V]) ComputeMultiProofForIndices(values []V, indices []int) (*MultiProof[V], error) {
+	data, err := valuesBytes(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.computeMultiProof(data, append([]int(nil), indices...))
+}
+
+func (t *Hasher[V]) computeMultiProof(data [][]byte, indices []int) (*MultiProof[V], error) {
+	if len(indices) < 1 {
+		return nil, ierrors.New("you need at least 1 index to create an inclusion proof")
+	}
+
+	sort.Ints(indices)
+	for i, index := range indices {
+		if index < 0 || index >= len(data) {
+			return nil, ierrors.Errorf("index %d out of bounds len=%d", index, len(data))
+		}
+		if i > 0 && index == indices[i-1] {
+			return nil, ierrors.Errorf("index %d is contained more than once", index)
+		}
+	}
+
+	proof := &MultiProof[V]{
+		LeafCount: uint32(len(data)),
+		Indices:   make([]uint32, len(indices)),
+		Hashes:    make([]MultiProofHash[V], 0),
+	}
+	for i, index := range indices {
+		proof.Indices[i] = uint32(index)
+	}
+	t.collectMultiProofHashes(data, indices, proof)
+
+	return proof, nil
+}
+
+// collectMultiProofHashes appends the hashes of the subtrees which contain none of the indices to the proof,
+// traversing the tree depth-first from left to right.
+func (t *Hasher[V]) collectMultiProofHashes(data [][]byte, indices []int, proof *MultiProof[V]) {
+	if len(indices) == 0 {
+		proof.Hashes = append(proof.Hashes, t.Hash(data))
+
+		return
+	}
+
+	if len(data) == 1 {
+		return
+	}
+
+	k := largestPowerOfTwo(len(data))
+	split := sort.SearchInts(indices, k)
+
+	rightIndices := make([]int, len(indices)-split)
+	for i, index := range indices[split:] {
+		rightIndices[i] = index - k
+	}
+
+	t.collectMultiProofHashes(data[:k], indices[:split], proof)
+	t.collectMultiProofHashes(data[k:], rightIndices, proof)
+}
+
+// Hash computes the root of the tree given the proven values in the order of the indices of the proof.
+func (p *MultiProof[V]) Hash(values []V, hasher *Hasher[V]) ([]byte, error) {
+	if p.LeafCount < 1 {
+		return nil, ierrors.New("proof contains no leaves")
+	}
+	if len(values) != len(p.Indices) {
+		return nil, ierrors.Errorf("proof contains %d indices but %d values were given", len(p.Indices), len(values))
+	}
+	for i, index := range p.Indices {
+		if index >= p.LeafCount {
+			return nil, ierrors.Errorf("index %d out of bounds len=%d", index, p.LeafCount)
+		}
+		if i > 0 && index <= p.Indices[i-1] {
+			return nil, ierrors.New("indices are not in strictly ascending order")
+		}
+	}
+
+	data, err := valuesBytes(values)
+	if err != nil {
+		return nil, err
+	}
+
+	valueHashes := make([][]byte, len(data))
+	for i := range data {
+		valueHashes[i] = hasher.hashLeaf(data[i])
+	}
+
+	hashes := p.Hashes
+	root := p.hash(hasher, 0, int(p.LeafCount), p.Indices, valueHashes, &hashes)
+	if root == nil {
+		return nil, ierrors.New("proof does not contain enough hashes")
+	}
+	if len(hashes) != 0 {
+		return nil, ierrors.Errorf("proof contains %d unused hashes", len(hashes))
+	}
+
+	return root, nil
+}
+
+// hash computes the hash of the subtree with the given amount of leaves starting at the given offset.
+// It returns nil if the proof doesn't contain enough hashes.
+func (p *MultiProof[V]) hash(hasher *Hasher[V], offset int, leafCount int, indices []uint32, valueHashes [][]byte, hashes *[]MultiProofHash[V]) []byte {
+	if len(indices) == 0 {
+		if len(*hashes) == 0 {
+			return nil
+		}
+
+		hash := (*hashes)[0]
+		*hashes = (*hashes)[1:]
+
+		return hash
+	}
+
+	if leafCount == 1 {
+		return valueHashes[0]
+	}
+
+	k := largestPowerOfTwo(leafCount)
+	split := sort.Search(len(indices), func(i int) bool { return int(indices[i]) >= offset+k })
+
+	left := p.hash(hasher, offset, k, indices[:split], valueHashes[:split], hashes)
+	if left == nil {
+		return nil
+	}
+
+	right := p.hash(hasher, offset+k, leafCount-k, indices[split:], valueHashes[split:], hashes)
+	if right == nil {
+		return nil
+	}
+
+	return hasher.hashNode(left, right)
+}
+
+// Verify checks that the given values, in the order of the indices of the proof, are contained in the tree with the given root.
+func (p *MultiProof[V]) Verify(values []V, root []byte, hasher *Hasher[V]) (bool, error) {
+	hash, err := p.Hash(values, hasher)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(hash, root), nil
+}
+
+// MultiProofFromJSON decodes a MultiProof from its JSON encoding.
+func MultiProofFromJSON[V Value](bytes []byte) (*MultiProof[V], error) {
+	p := new(MultiProof[V])
+	if err := serixAPI[V]().JSONDecode(context.TODO(), bytes, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// MultiProofFromBytes decodes a MultiProof from its binary encoding and returns the amount of consumed bytes.
+func MultiProofFromBytes[V Value](bytes []byte) (*MultiProof[V], int, error) {
+	p := new(MultiProof[V])
+	count, err := serixAPI[V]().Decode(context.TODO(), bytes, p)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return p, count, nil
+}
+
+// JSONEncode returns the JSON encoding of the MultiProof.
+func (p *MultiProof[V]) JSONEncode() ([]byte, error) {
+	return serixAPI[V]().JSONEncode(context.TODO(), p)
+}
+
+// Bytes returns the binary encoding of the MultiProof.
+func (p *MultiProof[V]) Bytes() ([]byte, error) {
+	return serixAPI[V]().Encode(context.TODO(), p)
+}
+
+// valuesBytes returns the serialized values.
+func valuesBytes[V Value](values []V) ([][]byte, error) {
+	data := make([][]byte, len(values))
+	for i := range values {
+		valueBytes, err := values[i].Bytes()
+		if err != nil {
+			return nil, err
+		}
+		data[i] = valueBytes
+	}
+
+	return data, nil
+}
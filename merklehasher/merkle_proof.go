@@ -212,6 +212,10 @@ func RegisterSerixRules[V Value](api *serix.API) {
 	must(api.RegisterInterfaceObjects((*MerkleHashable[V])(nil), (*ValueHash[V])(nil)))
 	must(api.RegisterInterfaceObjects((*MerkleHashable[V])(nil), (*LeafHash[V])(nil)))
 	must(api.RegisterInterfaceObjects((*MerkleHashable[V])(nil), (*Node[V])(nil)))
+
+	must(api.RegisterTypeSettings(MultiProofHash[V]{},
+		serix.TypeSettings{}.WithLengthPrefixType(serix.LengthPrefixTypeAsByte),
+	))
 }
 
 func serixAPI[V Value]() *serix.API {
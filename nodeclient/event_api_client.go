@@ -6,11 +6,13 @@ import (
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
 	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
 	iotago "github.com/iotaledger/iota.go/v4"
 	"github.com/iotaledger/iota.go/v4/api"
 	"github.com/iotaledger/iota.go/v4/hexutil"
@@ -63,11 +65,90 @@ const (
 	EventAPISpentOutputsByUnlockConditionAndAddress = "outputs/unlock/{condition}/{address}/spent"
 )
 
+const (
+	// DefaultEventAPIReconnectInitialBackoff is the default backoff before the second attempt to reconnect.
+	DefaultEventAPIReconnectInitialBackoff = 500 * time.Millisecond
+	// DefaultEventAPIReconnectMaxBackoff is the default maximum backoff between two attempts to reconnect.
+	DefaultEventAPIReconnectMaxBackoff = 30 * time.Second
+	// DefaultEventAPIConnectionCheckInterval is the default interval in which the connection is checked.
+	DefaultEventAPIConnectionCheckInterval = 5 * time.Second
+
+	gapFillInfoTimeout = 5 * time.Second
+)
+
 var (
 	// ErrEventAPIClientInactive gets returned when an EventAPIClient is inactive.
 	ErrEventAPIClientInactive = ierrors.New("event api client is inactive")
+	// ErrEventAPIConnectionLost gets passed to the EventAPIConnectionStateCallback when the connection was lost.
+	ErrEventAPIConnectionLost = ierrors.New("event api connection lost")
 )
 
+// EventAPIConnectionState is the state of the connection of an EventAPIClient.
+type EventAPIConnectionState int
+
+const (
+	// EventAPIConnectionStateConnected denotes that the EventAPIClient is connected.
+	EventAPIConnectionStateConnected EventAPIConnectionState = iota
+	// EventAPIConnectionStateDisconnected denotes that the EventAPIClient lost the connection.
+	EventAPIConnectionStateDisconnected
+	// EventAPIConnectionStateReconnecting denotes that the EventAPIClient tries to reconnect.
+	EventAPIConnectionStateReconnecting
+)
+
+func (s EventAPIConnectionState) String() string {
+	switch s {
+	case EventAPIConnectionStateConnected:
+		return "connected"
+	case EventAPIConnectionStateDisconnected:
+		return "disconnected"
+	case EventAPIConnectionStateReconnecting:
+		return "reconnecting"
+	default:
+		return fmt.Sprintf("unknown connection state (%d)", s)
+	}
+}
+
+// EventAPIConnectionStateCallback gets called when the connection state of an EventAPIClient changes.
+// The error is set if the connection was lost or the attempt to reconnect failed.
+type EventAPIConnectionStateCallback func(state EventAPIConnectionState, err error)
+
+// EventAPIGapFillFunc gets called after an EventAPIClient reconnected with the range of slots
+// whose commitments were created while the EventAPIClient was disconnected.
+// Events of these slots might have been missed and can be backfilled, e.g. with Client.CommitmentUTXOChangesByIndex.
+type EventAPIGapFillFunc func(ctx context.Context, startSlot iotago.SlotIndex, endSlot iotago.SlotIndex)
+
+// WithEventAPIReconnectBackoff sets the backoff before the second attempt to reconnect,
+// which doubles with every further attempt up to the given maximum.
+func WithEventAPIReconnectBackoff(initialBackoff time.Duration, maxBackoff time.Duration) options.Option[EventAPIClient] {
+	return func(eac *EventAPIClient) {
+		eac.optsReconnectInitialBackoff = initialBackoff
+		eac.optsReconnectMaxBackoff = maxBackoff
+	}
+}
+
+// WithEventAPIConnectionCheckInterval sets the interval in which the connection is checked.
+func WithEventAPIConnectionCheckInterval(interval time.Duration) options.Option[EventAPIClient] {
+	return func(eac *EventAPIClient) {
+		eac.optsConnectionCheckInterval = interval
+	}
+}
+
+// WithEventAPIConnectionStateCallback sets the callback which gets called when the connection state changes.
+func WithEventAPIConnectionStateCallback(callback EventAPIConnectionStateCallback) options.Option[EventAPIClient] {
+	return func(eac *EventAPIClient) {
+		eac.optsConnectionStateCallback = callback
+	}
+}
+
+// WithEventAPIGapFillFunc sets the function which gets called with the slots that might have been missed after reconnecting.
+// The gap starts after the latest commitment known before the connection was lost. Without a subscription to the
+// latest commitments, the latest commitment is refreshed with Client.Info in every connection check.
+func WithEventAPIGapFillFunc(gapFillFunc EventAPIGapFillFunc) options.Option[EventAPIClient] {
+	return func(eac *EventAPIClient) {
+		eac.optsGapFillFunc = gapFillFunc
+	}
+}
+
 // EventAPIUnlockCondition denotes the different unlock conditions.
 type EventAPIUnlockCondition string
 
@@ -94,24 +175,31 @@ func brokerURLFromClient(nc *Client) string {
 	return fmt.Sprintf("%s%s/%s", baseURL, api.APIRoot, api.MQTTPluginName)
 }
 
-func newEventAPIClient(nc *Client) *EventAPIClient {
+func newEventAPIClient(nc *Client, opts ...options.Option[EventAPIClient]) *EventAPIClient {
+	eac := options.Apply(&EventAPIClient{
+		Client: nc,
+		Errors: make(chan error),
+	}, opts)
+
 	clientOpts := mqtt.NewClientOptions()
 	clientOpts.Order = false
 	clientOpts.ClientID = randMQTTClientID()
 	clientOpts.AddBroker(brokerURLFromClient(nc))
-	errChan := make(chan error)
-	clientOpts.OnConnectionLost = func(client mqtt.Client, err error) { sendErrOrDrop(errChan, err) }
+	// reconnects are handled by the EventAPIClient to restore the subscriptions
+	clientOpts.AutoReconnect = false
+	clientOpts.OnConnectionLost = func(client mqtt.Client, err error) { eac.connectionLost(err) }
+	eac.MQTTClient = mqtt.NewClient(clientOpts)
 
-	return &EventAPIClient{
-		Client:     nc,
-		MQTTClient: mqtt.NewClient(clientOpts),
-		Errors:     errChan,
-	}
+	return eac
 }
 
 // EventAPIClient represents a handle to retrieve channels for node events.
 // Any registration will panic if the EventAPIClient.Ctx is done or the client isn't connected.
-// Multiple calls to the same channel registration will override the previously created channel.
+// Multiple registrations of the same channel share the subscription of the topic and each receive every event.
+// The received events are shared between the subscribers and must not be modified.
+//
+// If the connection is lost, the EventAPIClient reconnects with an exponential backoff
+// and restores all subscriptions which were not closed.
 type EventAPIClient struct {
 	Client *Client
 
@@ -122,20 +210,39 @@ type EventAPIClient struct {
 	// A channel up on which errors are returned from within subscriptions or when the connection is lost.
 	// Errors are dropped silently if no receiver is listening for them or can consume them fast enough.
 	Errors chan error
+
+	// the active subscriptions by topic.
+	subscriptions      map[string]*topicSubscription
+	subscriptionsMutex sync.Mutex
+	// signals the connection monitor that the connection was lost.
+	connectionLostChan chan error
+	// cancels the connection monitor.
+	monitorCancel context.CancelFunc
+	// the slot of the latest commitment known to the EventAPIClient.
+	latestCommitmentSlot      iotago.SlotIndex
+	latestCommitmentSlotMutex sync.Mutex
+
+	optsReconnectInitialBackoff time.Duration
+	optsReconnectMaxBackoff     time.Duration
+	optsConnectionCheckInterval time.Duration
+	optsConnectionStateCallback EventAPIConnectionStateCallback
+	optsGapFillFunc             EventAPIGapFillFunc
 }
 
 // EventAPIClientSubscription holds any error that happened when trying to subscribe to an event.
 // It also allows to close the subscription to cleanly unsubscribe from the node.
 type EventAPIClientSubscription struct {
-	mqttClient mqtt.Client
-	topic      string
-	error      error
+	eventAPIClient *EventAPIClient
+	topic          string
+	subscriber     eventAPISubscriber
+	error          error
 }
 
-func newSubscription(client mqtt.Client, topic string) *EventAPIClientSubscription {
+func newSubscription(eac *EventAPIClient, topic string, subscriber eventAPISubscriber) *EventAPIClientSubscription {
 	return &EventAPIClientSubscription{
-		mqttClient: client,
-		topic:      topic,
+		eventAPIClient: eac,
+		topic:          topic,
+		subscriber:     subscriber,
 	}
 }
 
@@ -151,15 +258,13 @@ func (s *EventAPIClientSubscription) Error() error {
 }
 
 // Close allows to close the subscription to cleanly unsubscribe from the node.
+// The channel of the subscription gets closed, the other subscribers of the same topic are not affected.
 func (s *EventAPIClientSubscription) Close() error {
 	if s.error != nil {
 		return s.error
 	}
-	if token := s.mqttClient.Unsubscribe(s.topic); token.Wait() && token.Error() != nil {
-		return token.Error()
-	}
 
-	return nil
+	return s.eventAPIClient.unsubscribe(s.topic, s.subscriber)
 }
 
 func panicIfEventAPIClientInactive(neac *EventAPIClient) {
@@ -171,6 +276,11 @@ func panicIfEventAPIClientInactive(neac *EventAPIClient) {
 	}
 }
 
+// isActive returns whether the EventAPIClient is connected and its context is not done.
+func (eac *EventAPIClient) isActive() bool {
+	return eac.ctx != nil && eac.ctx.Err() == nil && eac.MQTTClient.IsConnected()
+}
+
 func sendErrOrDrop(errChan chan error, err error) {
 	select {
 	case errChan <- err:
@@ -186,39 +296,236 @@ func (eac *EventAPIClient) Connect(ctx context.Context) error {
 		return token.Error()
 	}
 
+	if eac.optsGapFillFunc != nil {
+		// the latest commitment is the start of a possible gap after the connection was lost
+		infoCtx, cancelFunc := context.WithTimeout(ctx, gapFillInfoTimeout)
+		defer cancelFunc()
+
+		info, err := eac.Client.Info(infoCtx)
+		if err != nil {
+			return ierrors.Wrap(err, "failed to get the latest commitment")
+		}
+		eac.updateLatestCommitmentSlot(info.Status.LatestCommitmentID.Slot())
+	}
+
+	eac.startConnectionMonitor()
+
 	return nil
 }
 
-// Close disconnects the underlying MQTT client.
+// Close disconnects the underlying MQTT client and closes the channels of all subscriptions.
 // Call this function to clean up any registered channels.
 func (eac *EventAPIClient) Close() {
+	if eac.monitorCancel != nil {
+		eac.monitorCancel()
+	}
+
 	eac.MQTTClient.Disconnect(0)
+
+	eac.subscriptionsMutex.Lock()
+	subscriptions := eac.subscriptions
+	eac.subscriptions = nil
+	eac.subscriptionsMutex.Unlock()
+
+	for _, topicSub := range subscriptions {
+		for _, sub := range topicSub.subscribers {
+			sub.close()
+		}
+	}
 }
 
-func subscribeToTopic[T any](eac *EventAPIClient, topic string, deseriFunc func(payload []byte) (T, error)) (<-chan T, *EventAPIClientSubscription) {
-	panicIfEventAPIClientInactive(eac)
-	channel := make(chan T)
-	if token := eac.MQTTClient.Subscribe(topic, 2, func(client mqtt.Client, mqttMsg mqtt.Message) {
-		obj, err := deseriFunc(mqttMsg.Payload())
-		if err != nil {
-			sendErrOrDrop(eac.Errors, err)
+// startConnectionMonitor starts the goroutine which reconnects the EventAPIClient if the connection is lost.
+func (eac *EventAPIClient) startConnectionMonitor() {
+	if eac.optsReconnectInitialBackoff == 0 {
+		eac.optsReconnectInitialBackoff = DefaultEventAPIReconnectInitialBackoff
+	}
+	if eac.optsReconnectMaxBackoff == 0 {
+		eac.optsReconnectMaxBackoff = DefaultEventAPIReconnectMaxBackoff
+	}
+	if eac.optsConnectionCheckInterval == 0 {
+		eac.optsConnectionCheckInterval = DefaultEventAPIConnectionCheckInterval
+	}
+
+	eac.subscriptionsMutex.Lock()
+	if eac.connectionLostChan == nil {
+		eac.connectionLostChan = make(chan error, 1)
+	}
+	eac.subscriptionsMutex.Unlock()
+
+	if eac.monitorCancel != nil {
+		eac.monitorCancel()
+	}
 
+	var monitorCtx context.Context
+	monitorCtx, eac.monitorCancel = context.WithCancel(eac.ctx)
+
+	go eac.monitorConnection(monitorCtx)
+}
+
+// connectionLost gets called by the MQTT client when the connection was lost.
+func (eac *EventAPIClient) connectionLost(err error) {
+	sendErrOrDrop(eac.Errors, err)
+
+	eac.subscriptionsMutex.Lock()
+	defer eac.subscriptionsMutex.Unlock()
+
+	if eac.connectionLostChan != nil {
+		sendErrOrDrop(eac.connectionLostChan, err)
+	}
+}
+
+// monitorConnection reconnects the EventAPIClient if the connection was lost until the given context is done.
+func (eac *EventAPIClient) monitorConnection(ctx context.Context) {
+	ticker := time.NewTicker(eac.optsConnectionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
+		case err := <-eac.connectionLostChan:
+			// the connection might already be restored if the loss was detected by the check before
+			if !eac.MQTTClient.IsConnected() {
+				eac.reconnect(ctx, err)
+			}
+		case <-ticker.C:
+			if !eac.MQTTClient.IsConnected() {
+				eac.reconnect(ctx, ErrEventAPIConnectionLost)
+				continue
+			}
+			eac.refreshLatestCommitmentSlot(ctx)
 		}
+	}
+}
+
+// reconnect reconnects with an exponential backoff, restores the subscriptions and calls the gap-fill function.
+func (eac *EventAPIClient) reconnect(ctx context.Context, err error) {
+	eac.notifyConnectionState(EventAPIConnectionStateDisconnected, err)
+
+	backoff := eac.optsReconnectInitialBackoff
+	for {
+		eac.notifyConnectionState(EventAPIConnectionStateReconnecting, nil)
 
+		token := eac.MQTTClient.Connect()
+		if !token.Wait() || token.Error() == nil {
+			break
+		}
+		eac.notifyConnectionState(EventAPIConnectionStateDisconnected, token.Error())
+		sendErrOrDrop(eac.Errors, token.Error())
+
+		timer := time.NewTimer(backoff)
 		select {
-		case <-eac.ctx.Done():
+		case <-ctx.Done():
+			timer.Stop()
 			return
-		case channel <- obj:
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > eac.optsReconnectMaxBackoff {
+			backoff = eac.optsReconnectMaxBackoff
+		}
+	}
+
+	eac.resubscribe()
+	eac.notifyConnectionState(EventAPIConnectionStateConnected, nil)
+	eac.fillGap(ctx)
+}
+
+// resubscribe restores the active subscriptions after a reconnect.
+func (eac *EventAPIClient) resubscribe() {
+	// the lock is not held while subscribing, as the handlers need it to deliver the events received in the meantime
+	eac.subscriptionsMutex.Lock()
+	handlers := make(map[string]mqtt.MessageHandler, len(eac.subscriptions))
+	for topic, topicSub := range eac.subscriptions {
+		handlers[topic] = topicSub.handler
+	}
+	eac.subscriptionsMutex.Unlock()
+
+	for topic, handler := range handlers {
+		if token := eac.MQTTClient.Subscribe(topic, 2, handler); token.Wait() && token.Error() != nil {
+			sendErrOrDrop(eac.Errors, ierrors.Wrapf(token.Error(), "failed to restore subscription to %s", topic))
+		}
+	}
+}
+
+// fillGap calls the gap-fill function with the slots whose commitments were created since the latest known commitment.
+func (eac *EventAPIClient) fillGap(ctx context.Context) {
+	if eac.optsGapFillFunc == nil {
+		return
+	}
+
+	infoCtx, cancelFunc := context.WithTimeout(ctx, gapFillInfoTimeout)
+	defer cancelFunc()
+
+	info, err := eac.Client.Info(infoCtx)
+	if err != nil {
+		sendErrOrDrop(eac.Errors, ierrors.Wrap(err, "failed to get the latest commitment to fill the gap"))
+		return
+	}
+
+	eac.latestCommitmentSlotMutex.Lock()
+	startSlot := eac.latestCommitmentSlot + 1
+	endSlot := info.Status.LatestCommitmentID.Slot()
+	if endSlot > eac.latestCommitmentSlot {
+		eac.latestCommitmentSlot = endSlot
+	}
+	eac.latestCommitmentSlotMutex.Unlock()
+
+	if endSlot >= startSlot {
+		eac.optsGapFillFunc(ctx, startSlot, endSlot)
+	}
+}
+
+// refreshLatestCommitmentSlot updates the latest commitment known to the EventAPIClient with the info of the node,
+// so the gap after a connection loss starts at the latest commitment before the loss.
+// It is not needed if the latest commitments are received by a subscription.
+func (eac *EventAPIClient) refreshLatestCommitmentSlot(ctx context.Context) {
+	if eac.optsGapFillFunc == nil || eac.hasLatestCommitmentSubscription() {
+		return
+	}
+
+	infoCtx, cancelFunc := context.WithTimeout(ctx, gapFillInfoTimeout)
+	defer cancelFunc()
+
+	info, err := eac.Client.Info(infoCtx)
+	if err != nil {
+		sendErrOrDrop(eac.Errors, ierrors.Wrap(err, "failed to refresh the latest commitment"))
+		return
+	}
+	eac.updateLatestCommitmentSlot(info.Status.LatestCommitmentID.Slot())
+}
+
+// hasLatestCommitmentSubscription returns whether the latest commitments are received by a subscription.
+func (eac *EventAPIClient) hasLatestCommitmentSubscription() bool {
+	eac.subscriptionsMutex.Lock()
+	defer eac.subscriptionsMutex.Unlock()
+
+	for _, topic := range []string{EventAPICommitments, EventAPICommitmentInfoLatest} {
+		if _, exists := eac.subscriptions[topic]; exists {
+			return true
 		}
-	}); token.Wait() && token.Error() != nil {
-		return nil, newSubscriptionWithError(token.Error())
 	}
 
-	return channel, newSubscription(eac.MQTTClient, topic)
+	return false
+}
+
+func (eac *EventAPIClient) notifyConnectionState(state EventAPIConnectionState, err error) {
+	if eac.optsConnectionStateCallback != nil {
+		eac.optsConnectionStateCallback(state, err)
+	}
+}
+
+// updateLatestCommitmentSlot sets the slot of the latest commitment known to the EventAPIClient.
+func (eac *EventAPIClient) updateLatestCommitmentSlot(slot iotago.SlotIndex) {
+	eac.latestCommitmentSlotMutex.Lock()
+	defer eac.latestCommitmentSlotMutex.Unlock()
+
+	if slot > eac.latestCommitmentSlot {
+		eac.latestCommitmentSlot = slot
+	}
 }
 
-func (eac *EventAPIClient) subscribeToOutputsTopic(topic string) (<-chan iotago.Output, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) subscribeToOutputsTopic(topic string, opts ...SubscriptionOption) (<-chan iotago.Output, *EventAPIClientSubscription) {
 	return subscribeToTopic(eac, topic, func(payload []byte) (iotago.Output, error) {
 		var output iotago.TxEssenceOutput
 		if err := eac.Client.CommittedAPI().JSONDecode(payload, &output); err != nil {
@@ -227,10 +534,10 @@ func (eac *EventAPIClient) subscribeToOutputsTopic(topic string) (<-chan iotago.
 		}
 
 		return output, nil
-	})
+	}, opts...)
 }
 
-func (eac *EventAPIClient) subscribeToBlockMetadataTopic(topic string) (<-chan *api.BlockMetadataResponse, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) subscribeToBlockMetadataTopic(topic string, opts ...SubscriptionOption) (<-chan *api.BlockMetadataResponse, *EventAPIClientSubscription) {
 	return subscribeToTopic(eac, topic, func(payload []byte) (*api.BlockMetadataResponse, error) {
 		response := new(api.BlockMetadataResponse)
 		if err := eac.Client.CommittedAPI().JSONDecode(payload, response); err != nil {
@@ -239,10 +546,10 @@ func (eac *EventAPIClient) subscribeToBlockMetadataTopic(topic string) (<-chan *
 		}
 
 		return response, nil
-	})
+	}, opts...)
 }
 
-func (eac *EventAPIClient) subscribeToBlocksTopic(topic string) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) subscribeToBlocksTopic(topic string, opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
 	return subscribeToTopic(eac, topic, func(payload []byte) (*iotago.Block, error) {
 		version, _, err := iotago.VersionFromBytes(payload)
 		if err != nil {
@@ -262,113 +569,161 @@ func (eac *EventAPIClient) subscribeToBlocksTopic(topic string) (<-chan *iotago.
 		}
 
 		return block, nil
-	})
+	}, opts...)
+}
+
+func (eac *EventAPIClient) subscribeToCommitmentInfoTopic(topic string, opts ...SubscriptionOption) (<-chan *api.CommitmentInfoResponse, *EventAPIClientSubscription) {
+	return subscribeToTopic(eac, topic, func(payload []byte) (*api.CommitmentInfoResponse, error) {
+		response := new(api.CommitmentInfoResponse)
+		if err := eac.Client.CommittedAPI().JSONDecode(payload, response); err != nil {
+			sendErrOrDrop(eac.Errors, err)
+			return nil, err
+		}
+		eac.updateLatestCommitmentSlot(response.CommitmentSlot)
+
+		return response, nil
+	}, opts...)
+}
+
+// Commitments returns a channel of newly created slot commitments.
+func (eac *EventAPIClient) Commitments(opts ...SubscriptionOption) (<-chan *iotago.Commitment, *EventAPIClientSubscription) {
+	return subscribeToTopic(eac, EventAPICommitments, func(payload []byte) (*iotago.Commitment, error) {
+		version, _, err := iotago.VersionFromBytes(payload)
+		if err != nil {
+			sendErrOrDrop(eac.Errors, err)
+			return nil, err
+		}
+		apiForVersion, err := eac.Client.apiProvider.APIForVersion(version)
+		if err != nil {
+			sendErrOrDrop(eac.Errors, err)
+			return nil, err
+		}
+
+		commitment := new(iotago.Commitment)
+		if _, err := apiForVersion.Decode(payload, commitment); err != nil {
+			sendErrOrDrop(eac.Errors, err)
+			return nil, err
+		}
+		eac.updateLatestCommitmentSlot(commitment.Slot)
+
+		return commitment, nil
+	}, opts...)
+}
+
+// LatestCommitmentInfo returns a channel of the ID and slot of the latest commitment each time it changes.
+func (eac *EventAPIClient) LatestCommitmentInfo(opts ...SubscriptionOption) (<-chan *api.CommitmentInfoResponse, *EventAPIClientSubscription) {
+	return eac.subscribeToCommitmentInfoTopic(EventAPICommitmentInfoLatest, opts...)
+}
+
+// FinalizedCommitmentInfo returns a channel of the ID and slot of the latest finalized commitment each time it changes.
+func (eac *EventAPIClient) FinalizedCommitmentInfo(opts ...SubscriptionOption) (<-chan *api.CommitmentInfoResponse, *EventAPIClientSubscription) {
+	return eac.subscribeToCommitmentInfoTopic(EventAPICommitmentInfoFinalized, opts...)
 }
 
 // Blocks returns a channel of newly received blocks.
-func (eac *EventAPIClient) Blocks() (<-chan *iotago.Block, *EventAPIClientSubscription) {
-	return eac.subscribeToBlocksTopic(EventAPIBlocks)
+func (eac *EventAPIClient) Blocks(opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+	return eac.subscribeToBlocksTopic(EventAPIBlocks, opts...)
 }
 
 // AcceptedBlocks returns a channel of blocks of newly accepted blocks.
-func (eac *EventAPIClient) AcceptedBlocks() (<-chan *iotago.Block, *EventAPIClientSubscription) {
-	return eac.subscribeToBlocksTopic(EventAPIBlocksAccepted)
+func (eac *EventAPIClient) AcceptedBlocks(opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+	return eac.subscribeToBlocksTopic(EventAPIBlocksAccepted, opts...)
 }
 
 // ConfirmedBlocks returns a channel of blocks of newly confirmed blocks.
-func (eac *EventAPIClient) ConfirmedBlocks() (<-chan *iotago.Block, *EventAPIClientSubscription) {
-	return eac.subscribeToBlocksTopic(EventAPIBlocksConfirmed)
+func (eac *EventAPIClient) ConfirmedBlocks(opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+	return eac.subscribeToBlocksTopic(EventAPIBlocksConfirmed, opts...)
 }
 
 // TransactionBlocks returns a channel of blocks containing transactions.
-func (eac *EventAPIClient) TransactionBlocks() (<-chan *iotago.Block, *EventAPIClientSubscription) {
-	return eac.subscribeToBlocksTopic(EventAPIBlocksTransaction)
+func (eac *EventAPIClient) TransactionBlocks(opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+	return eac.subscribeToBlocksTopic(EventAPIBlocksTransaction, opts...)
 }
 
 // TransactionTaggedDataBlocks returns a channel of blocks containing transactions with tagged data.
-func (eac *EventAPIClient) TransactionTaggedDataBlocks() (<-chan *iotago.Block, *EventAPIClientSubscription) {
-	return eac.subscribeToBlocksTopic(EventAPIBlocksTransactionTaggedData)
+func (eac *EventAPIClient) TransactionTaggedDataBlocks(opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+	return eac.subscribeToBlocksTopic(EventAPIBlocksTransactionTaggedData, opts...)
 }
 
 // TransactionTaggedDataWithTagBlocks returns a channel of blocks containing transactions with tagged data containing the given tag.
-func (eac *EventAPIClient) TransactionTaggedDataWithTagBlocks(tag []byte) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) TransactionTaggedDataWithTagBlocks(tag []byte, opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIBlocksTransactionTaggedDataTag, "{tag}", hexutil.EncodeHex(tag), 1)
 
-	return eac.subscribeToBlocksTopic(topic)
+	return eac.subscribeToBlocksTopic(topic, opts...)
 }
 
 // TaggedDataBlocks returns a channel of blocks containing tagged data containing the given tag.
-func (eac *EventAPIClient) TaggedDataBlocks() (<-chan *iotago.Block, *EventAPIClientSubscription) {
-	return eac.subscribeToBlocksTopic(EventAPIBlocksTaggedData)
+func (eac *EventAPIClient) TaggedDataBlocks(opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+	return eac.subscribeToBlocksTopic(EventAPIBlocksTaggedData, opts...)
 }
 
 // TaggedDataWithTagBlocks returns a channel of blocks containing tagged data.
-func (eac *EventAPIClient) TaggedDataWithTagBlocks(tag []byte) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) TaggedDataWithTagBlocks(tag []byte, opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIBlocksTaggedDataTag, "{tag}", hexutil.EncodeHex(tag), 1)
 
-	return eac.subscribeToBlocksTopic(topic)
+	return eac.subscribeToBlocksTopic(topic, opts...)
 }
 
 // BlockMetadataChange returns a channel of BlockMetadataResponse each time the given block's state changes.
-func (eac *EventAPIClient) BlockMetadataChange(blockID iotago.BlockID) (<-chan *api.BlockMetadataResponse, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) BlockMetadataChange(blockID iotago.BlockID, opts ...SubscriptionOption) (<-chan *api.BlockMetadataResponse, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIBlockMetadata, "{blockId}", blockID.ToHex(), 1)
 
-	return eac.subscribeToBlockMetadataTopic(topic)
+	return eac.subscribeToBlockMetadataTopic(topic, opts...)
 }
 
 // NFTOutputsByID returns a channel of newly created outputs to track the chain mutations of a given NFT.
-func (eac *EventAPIClient) NFTOutputsByID(nftID iotago.NFTID) (<-chan iotago.Output, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) NFTOutputsByID(nftID iotago.NFTID, opts ...SubscriptionOption) (<-chan iotago.Output, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPINFTOutputs, "{nftId}", nftID.String(), 1)
 
-	return eac.subscribeToOutputsTopic(topic)
+	return eac.subscribeToOutputsTopic(topic, opts...)
 }
 
 // AccountOutputsByID returns a channel of newly created outputs to track the chain mutations of a given Account.
-func (eac *EventAPIClient) AccountOutputsByID(accountID iotago.AccountID) (<-chan iotago.Output, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) AccountOutputsByID(accountID iotago.AccountID, opts ...SubscriptionOption) (<-chan iotago.Output, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIAccountOutputs, "{accountId}", accountID.String(), 1)
 
-	return eac.subscribeToOutputsTopic(topic)
+	return eac.subscribeToOutputsTopic(topic, opts...)
 }
 
 // FoundryOutputsByID returns a channel of newly created outputs to track the chain mutations of a given Foundry.
-func (eac *EventAPIClient) FoundryOutputsByID(foundryID iotago.FoundryID) (<-chan iotago.Output, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) FoundryOutputsByID(foundryID iotago.FoundryID, opts ...SubscriptionOption) (<-chan iotago.Output, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIFoundryOutputs, "{foundryId}", foundryID.String(), 1)
 
-	return eac.subscribeToOutputsTopic(topic)
+	return eac.subscribeToOutputsTopic(topic, opts...)
 }
 
 // OutputsByUnlockConditionAndAddress returns a channel of newly created outputs on the given unlock condition and address.
-func (eac *EventAPIClient) OutputsByUnlockConditionAndAddress(addr iotago.Address, netPrefix iotago.NetworkPrefix, condition EventAPIUnlockCondition) (<-chan iotago.Output, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) OutputsByUnlockConditionAndAddress(addr iotago.Address, netPrefix iotago.NetworkPrefix, condition EventAPIUnlockCondition, opts ...SubscriptionOption) (<-chan iotago.Output, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIOutputsByUnlockConditionAndAddress, "{address}", addr.Bech32(netPrefix), 1)
 	topic = strings.Replace(topic, "{condition}", string(condition), 1)
 
-	return eac.subscribeToOutputsTopic(topic)
+	return eac.subscribeToOutputsTopic(topic, opts...)
 }
 
 // SpentOutputsByUnlockConditionAndAddress returns a channel of newly spent outputs on the given unlock condition and address.
-func (eac *EventAPIClient) SpentOutputsByUnlockConditionAndAddress(addr iotago.Address, netPrefix iotago.NetworkPrefix, condition EventAPIUnlockCondition) (<-chan iotago.Output, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) SpentOutputsByUnlockConditionAndAddress(addr iotago.Address, netPrefix iotago.NetworkPrefix, condition EventAPIUnlockCondition, opts ...SubscriptionOption) (<-chan iotago.Output, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPISpentOutputsByUnlockConditionAndAddress, "{address}", addr.Bech32(netPrefix), 1)
 	topic = strings.Replace(topic, "{condition}", string(condition), 1)
 
-	return eac.subscribeToOutputsTopic(topic)
+	return eac.subscribeToOutputsTopic(topic, opts...)
 }
 
 // TransactionIncludedBlock returns a channel of the included block which carries the transaction with the given ID.
-func (eac *EventAPIClient) TransactionIncludedBlock(txID iotago.TransactionID) (<-chan *iotago.Block, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) TransactionIncludedBlock(txID iotago.TransactionID, opts ...SubscriptionOption) (<-chan *iotago.Block, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPITransactionsIncludedBlock, "{transactionId}", txID.ToHex(), 1)
 
-	return eac.subscribeToBlocksTopic(topic)
+	return eac.subscribeToBlocksTopic(topic, opts...)
 }
 
 // Output returns a channel which immediately returns the output with the given ID and afterward when its state changes.
-func (eac *EventAPIClient) Output(outputID iotago.OutputID) (<-chan iotago.Output, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) Output(outputID iotago.OutputID, opts ...SubscriptionOption) (<-chan iotago.Output, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIOutputs, "{outputId}", hexutil.EncodeHex(outputID[:]), 1)
 
-	return eac.subscribeToOutputsTopic(topic)
+	return eac.subscribeToOutputsTopic(topic, opts...)
 }
 
 // OutputMetadata returns a channel which immediately returns the output metadata with the given ID and afterward when its state changes.
-func (eac *EventAPIClient) OutputMetadata(outputID iotago.OutputID) (<-chan *api.OutputMetadata, *EventAPIClientSubscription) {
+func (eac *EventAPIClient) OutputMetadata(outputID iotago.OutputID, opts ...SubscriptionOption) (<-chan *api.OutputMetadata, *EventAPIClientSubscription) {
 	topic := strings.Replace(EventAPIOutputMetadata, "{outputId}", hexutil.EncodeHex(outputID[:]), 1)
 
 	return subscribeToTopic(eac, topic, func(payload []byte) (*api.OutputMetadata, error) {
@@ -379,5 +734,5 @@ func (eac *EventAPIClient) OutputMetadata(outputID iotago.OutputID) (<-chan *api
 		}
 
 		return response, nil
-	})
+	}, opts...)
 }
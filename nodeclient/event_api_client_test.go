@@ -3,6 +3,7 @@ package nodeclient_test
 import (
 	"context"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/h2non/gock.v1"
 
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
 	iotago "github.com/iotaledger/iota.go/v4"
 	"github.com/iotaledger/iota.go/v4/api"
 	"github.com/iotaledger/iota.go/v4/nodeclient"
@@ -143,3 +146,561 @@ func (m *mockMqttClient) Unsubscribe(...string) mqtt.Token {
 func (m *mockMqttClient) AddRoute(_ string, _ mqtt.MessageHandler) { panic("implement me") }
 
 func (m *mockMqttClient) OptionsReader() mqtt.ClientOptionsReader { panic("implement me") }
+
+func Test_EventAPIClientCommitments(t *testing.T) {
+	commitment := tpkg.RandCommitment()
+	commitment.ProtocolVersion = mockAPI.Version()
+	commitmentBytes, err := mockAPI.Encode(commitment)
+	require.NoError(t, err)
+
+	latestCommitmentInfo := &api.CommitmentInfoResponse{CommitmentID: commitment.MustID(), CommitmentSlot: commitment.Slot}
+	finalizedCommitmentInfo := &api.CommitmentInfoResponse{CommitmentID: tpkg.RandCommitmentID(), CommitmentSlot: commitment.Slot - 1}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	eventAPIClient := &nodeclient.EventAPIClient{
+		Client: nodeClient(t),
+		MQTTClient: &topicMqttClient{payloads: map[string][][]byte{
+			nodeclient.EventAPICommitments:             {commitmentBytes},
+			nodeclient.EventAPICommitmentInfoLatest:    {lo.PanicOnErr(mockAPI.JSONEncode(latestCommitmentInfo))},
+			nodeclient.EventAPICommitmentInfoFinalized: {lo.PanicOnErr(mockAPI.JSONEncode(finalizedCommitmentInfo))},
+		}},
+		Errors: make(chan error),
+	}
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	commitmentChan, sub := eventAPIClient.Commitments()
+	require.NoError(t, sub.Error())
+	select {
+	case receivedCommitment := <-commitmentChan:
+		require.Equal(t, commitment.MustID(), receivedCommitment.MustID())
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "commitment not received")
+	}
+
+	latestChan, sub := eventAPIClient.LatestCommitmentInfo()
+	require.NoError(t, sub.Error())
+	select {
+	case receivedInfo := <-latestChan:
+		require.Equal(t, latestCommitmentInfo, receivedInfo)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "latest commitment info not received")
+	}
+
+	finalizedChan, sub := eventAPIClient.FinalizedCommitmentInfo()
+	require.NoError(t, sub.Error())
+	select {
+	case receivedInfo := <-finalizedChan:
+		require.Equal(t, finalizedCommitmentInfo, receivedInfo)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "finalized commitment info not received")
+	}
+}
+
+// reconnectingMqttClient is a mockMqttClient whose connection can be dropped and whose connection attempts can fail.
+type reconnectingMqttClient struct {
+	mockMqttClient
+
+	mutex          sync.Mutex
+	connected      bool
+	failConnects   int
+	connects       int
+	subscriptions  map[string]int
+	unsubscribed   []string
+	connectFailure error
+}
+
+func (m *reconnectingMqttClient) IsConnected() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.connected
+}
+
+func (m *reconnectingMqttClient) Connect() mqtt.Token {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.connects++
+	if m.failConnects > 0 {
+		m.failConnects--
+		return &failedToken{err: m.connectFailure}
+	}
+	m.connected = true
+
+	return &mockToken{}
+}
+
+func (m *reconnectingMqttClient) Subscribe(topic string, _ byte, _ mqtt.MessageHandler) mqtt.Token {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.subscriptions[topic]++
+
+	return &mockToken{}
+}
+
+func (m *reconnectingMqttClient) Unsubscribe(topics ...string) mqtt.Token {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.unsubscribed = append(m.unsubscribed, topics...)
+
+	return &mockToken{}
+}
+
+// dropConnection drops the connection and lets the given amount of following connection attempts fail.
+func (m *reconnectingMqttClient) dropConnection(failConnects int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.connected = false
+	m.failConnects = failConnects
+}
+
+func (m *reconnectingMqttClient) subscriptionCount(topic string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.subscriptions[topic]
+}
+
+type failedToken struct {
+	mockToken
+	err error
+}
+
+func (f *failedToken) Wait() bool { return true }
+
+func (f *failedToken) Error() error { return f.err }
+
+func mockInfoWithLatestCommitmentSlot(slot iotago.SlotIndex) {
+	mockGetJSON(api.CoreRouteInfo, 200, &api.InfoResponse{
+		Name:    "HORNET",
+		Version: "1.0.0",
+		Status: &api.InfoResNodeStatus{
+			IsHealthy:          true,
+			LatestCommitmentID: iotago.NewCommitmentID(slot, iotago.Identifier{}),
+		},
+		ProtocolParameters: []*api.InfoResProtocolParameters{
+			{StartEpoch: 0, Parameters: tpkg.IOTAMainnetV3TestProtocolParameters},
+		},
+		BaseToken: &api.InfoResBaseToken{Name: "TestCoin", TickerSymbol: "TEST", Unit: "TEST", Decimals: 6},
+		Metrics:   &api.InfoResNodeMetrics{},
+	})
+}
+
+func Test_EventAPIClientReconnect(t *testing.T) {
+	defer gock.Off()
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	mockGetJSON(api.RouteRoutes, 200, &api.RoutesResponse{Routes: []iotago.PrefixedStringUint8{api.MQTTPluginName}})
+
+	var statesMutex sync.Mutex
+	var states []nodeclient.EventAPIConnectionState
+	gapFilled := make(chan [2]iotago.SlotIndex, 1)
+
+	eventAPIClient, err := nodeClient(t).EventAPI(ctx,
+		nodeclient.WithEventAPIReconnectBackoff(time.Millisecond, 5*time.Millisecond),
+		nodeclient.WithEventAPIConnectionCheckInterval(10*time.Millisecond),
+		nodeclient.WithEventAPIConnectionStateCallback(func(state nodeclient.EventAPIConnectionState, _ error) {
+			statesMutex.Lock()
+			defer statesMutex.Unlock()
+
+			states = append(states, state)
+		}),
+		nodeclient.WithEventAPIGapFillFunc(func(_ context.Context, startSlot iotago.SlotIndex, endSlot iotago.SlotIndex) {
+			gapFilled <- [2]iotago.SlotIndex{startSlot, endSlot}
+		}),
+	)
+	require.NoError(t, err)
+
+	mqttClient := &reconnectingMqttClient{
+		subscriptions:  make(map[string]int),
+		connectFailure: ierrors.New("connection refused"),
+	}
+	eventAPIClient.MQTTClient = mqttClient
+
+	// the latest commitment is queried on connect to detect the missed slots later on
+	mockInfoWithLatestCommitmentSlot(10)
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	_, commitmentsSub := eventAPIClient.Commitments()
+	require.NoError(t, commitmentsSub.Error())
+	_, latestInfoSub := eventAPIClient.LatestCommitmentInfo()
+	require.NoError(t, latestInfoSub.Error())
+	_, finalizedInfoSub := eventAPIClient.FinalizedCommitmentInfo()
+	require.NoError(t, finalizedInfoSub.Error())
+
+	// closed subscriptions are not restored
+	require.NoError(t, latestInfoSub.Close())
+
+	mockInfoWithLatestCommitmentSlot(15)
+	mqttClient.dropConnection(2)
+
+	select {
+	case gap := <-gapFilled:
+		require.Equal(t, [2]iotago.SlotIndex{11, 15}, gap)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "gap not filled")
+	}
+
+	require.True(t, mqttClient.IsConnected())
+	require.Equal(t, 4, mqttClient.connects)
+	require.Equal(t, 2, mqttClient.subscriptionCount(nodeclient.EventAPICommitments))
+	require.Equal(t, 2, mqttClient.subscriptionCount(nodeclient.EventAPICommitmentInfoFinalized))
+	require.Equal(t, 1, mqttClient.subscriptionCount(nodeclient.EventAPICommitmentInfoLatest))
+	require.Equal(t, []string{nodeclient.EventAPICommitmentInfoLatest}, mqttClient.unsubscribed)
+
+	statesMutex.Lock()
+	defer statesMutex.Unlock()
+	require.Equal(t, []nodeclient.EventAPIConnectionState{
+		nodeclient.EventAPIConnectionStateDisconnected,
+		nodeclient.EventAPIConnectionStateReconnecting,
+		nodeclient.EventAPIConnectionStateDisconnected,
+		nodeclient.EventAPIConnectionStateReconnecting,
+		nodeclient.EventAPIConnectionStateDisconnected,
+		nodeclient.EventAPIConnectionStateReconnecting,
+		nodeclient.EventAPIConnectionStateConnected,
+	}, states)
+}
+
+func Test_EventAPIClientReconnectWithoutCommitmentSubscription(t *testing.T) {
+	defer gock.Off()
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	mockGetJSON(api.RouteRoutes, 200, &api.RoutesResponse{Routes: []iotago.PrefixedStringUint8{api.MQTTPluginName}})
+
+	gapFilled := make(chan [2]iotago.SlotIndex, 1)
+	eventAPIClient, err := nodeClient(t).EventAPI(ctx,
+		nodeclient.WithEventAPIReconnectBackoff(time.Millisecond, 5*time.Millisecond),
+		nodeclient.WithEventAPIConnectionCheckInterval(10*time.Millisecond),
+		nodeclient.WithEventAPIConnectionStateCallback(func(state nodeclient.EventAPIConnectionState, _ error) {
+			// the node created more commitments while the connection was lost
+			if state == nodeclient.EventAPIConnectionStateDisconnected {
+				mockInfoWithLatestCommitmentSlot(15)
+			}
+		}),
+		nodeclient.WithEventAPIGapFillFunc(func(_ context.Context, startSlot iotago.SlotIndex, endSlot iotago.SlotIndex) {
+			gapFilled <- [2]iotago.SlotIndex{startSlot, endSlot}
+		}),
+	)
+	require.NoError(t, err)
+
+	mqttClient := &reconnectingMqttClient{subscriptions: make(map[string]int)}
+	eventAPIClient.MQTTClient = mqttClient
+
+	mockInfoWithLatestCommitmentSlot(10)
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	_, blocksSub := eventAPIClient.Blocks()
+	require.NoError(t, blocksSub.Error())
+
+	// without a commitment subscription, the latest commitment is refreshed by the connection check
+	mockInfoWithLatestCommitmentSlot(12)
+	require.Eventually(t, gock.IsDone, 5*time.Second, 10*time.Millisecond)
+
+	mqttClient.dropConnection(0)
+
+	select {
+	case gap := <-gapFilled:
+		require.Equal(t, [2]iotago.SlotIndex{13, 15}, gap)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "gap not filled")
+	}
+}
+
+// brokerMqttClient is a mockMqttClient which delivers published payloads to the subscribed topics.
+type brokerMqttClient struct {
+	mockMqttClient
+
+	mutex        sync.Mutex
+	handlers     map[string]mqtt.MessageHandler
+	subscribes   map[string]int
+	unsubscribes map[string]int
+}
+
+func newBrokerMqttClient() *brokerMqttClient {
+	return &brokerMqttClient{
+		handlers:     make(map[string]mqtt.MessageHandler),
+		subscribes:   make(map[string]int),
+		unsubscribes: make(map[string]int),
+	}
+}
+
+func (m *brokerMqttClient) Subscribe(topic string, _ byte, callback mqtt.MessageHandler) mqtt.Token {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.handlers[topic] = callback
+	m.subscribes[topic]++
+
+	return &mockToken{}
+}
+
+func (m *brokerMqttClient) Unsubscribe(topics ...string) mqtt.Token {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, topic := range topics {
+		delete(m.handlers, topic)
+		m.unsubscribes[topic]++
+	}
+
+	return &mockToken{}
+}
+
+// publish synchronously delivers the payload to the handler of the topic.
+func (m *brokerMqttClient) publish(topic string, payload []byte) {
+	m.mutex.Lock()
+	handler, exists := m.handlers[topic]
+	m.mutex.Unlock()
+
+	if exists {
+		handler(m, &mockMsg{payload: payload})
+	}
+}
+
+func (m *brokerMqttClient) counts(topic string) (subscribes int, unsubscribes int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.subscribes[topic], m.unsubscribes[topic]
+}
+
+func requireReceived[T any](t *testing.T, channel <-chan T) T {
+	t.Helper()
+
+	select {
+	case obj, ok := <-channel:
+		require.True(t, ok, "channel closed")
+		return obj
+	default:
+		require.FailNow(t, "nothing received")
+		return *new(T)
+	}
+}
+
+func requireClosed[T any](t *testing.T, channel <-chan T) {
+	t.Helper()
+
+	select {
+	case _, ok := <-channel:
+		require.False(t, ok, "channel not closed")
+	default:
+		require.FailNow(t, "channel not closed")
+	}
+}
+
+func Test_EventAPIClientFanOut(t *testing.T) {
+	block := tpkg.RandBlock(tpkg.RandBasicBlockBody(tpkg.ZeroCostTestAPI, iotago.PayloadTaggedData), tpkg.ZeroCostTestAPI, 0)
+	blockBytes, err := tpkg.ZeroCostTestAPI.Encode(block)
+	require.NoError(t, err)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	mqttClient := newBrokerMqttClient()
+	eventAPIClient := &nodeclient.EventAPIClient{
+		Client:     nodeClient(t),
+		MQTTClient: mqttClient,
+		Errors:     make(chan error),
+	}
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	t.Run("shared topic", func(t *testing.T) {
+		blocks1, sub1 := eventAPIClient.Blocks(nodeclient.WithSubscriptionBufferSize(1))
+		require.NoError(t, sub1.Error())
+		blocks2, sub2 := eventAPIClient.Blocks(nodeclient.WithSubscriptionBufferSize(1))
+		require.NoError(t, sub2.Error())
+
+		subscribes, _ := mqttClient.counts(nodeclient.EventAPIBlocks)
+		require.Equal(t, 1, subscribes)
+
+		mqttClient.publish(nodeclient.EventAPIBlocks, blockBytes)
+		require.Equal(t, block.MustID(), requireReceived(t, blocks1).MustID())
+		require.Equal(t, block.MustID(), requireReceived(t, blocks2).MustID())
+
+		// closing one subscription doesn't affect the other one
+		require.NoError(t, sub1.Close())
+		require.NoError(t, sub1.Close())
+		requireClosed(t, blocks1)
+
+		mqttClient.publish(nodeclient.EventAPIBlocks, blockBytes)
+		require.Equal(t, block.MustID(), requireReceived(t, blocks2).MustID())
+
+		_, unsubscribes := mqttClient.counts(nodeclient.EventAPIBlocks)
+		require.Equal(t, 0, unsubscribes)
+
+		// the topic is unsubscribed with the last subscription
+		require.NoError(t, sub2.Close())
+		requireClosed(t, blocks2)
+
+		_, unsubscribes = mqttClient.counts(nodeclient.EventAPIBlocks)
+		require.Equal(t, 1, unsubscribes)
+	})
+
+	t.Run("slow consumer policy drop", func(t *testing.T) {
+		blocks, sub := eventAPIClient.AcceptedBlocks(nodeclient.WithSubscriptionBufferSize(1), nodeclient.WithSlowConsumerPolicy(nodeclient.SlowConsumerPolicyDrop))
+		require.NoError(t, sub.Error())
+		defer func() { _ = sub.Close() }()
+
+		mqttClient.publish(nodeclient.EventAPIBlocksAccepted, blockBytes)
+		mqttClient.publish(nodeclient.EventAPIBlocksAccepted, blockBytes)
+		requireReceived(t, blocks)
+		require.Empty(t, blocks)
+
+		// the subscription remains active
+		mqttClient.publish(nodeclient.EventAPIBlocksAccepted, blockBytes)
+		requireReceived(t, blocks)
+	})
+
+	t.Run("slow consumer policy close", func(t *testing.T) {
+		slowBlocks, slowSub := eventAPIClient.ConfirmedBlocks(nodeclient.WithSubscriptionBufferSize(1), nodeclient.WithSlowConsumerPolicy(nodeclient.SlowConsumerPolicyClose))
+		require.NoError(t, slowSub.Error())
+		blocks, sub := eventAPIClient.ConfirmedBlocks(nodeclient.WithSubscriptionBufferSize(2))
+		require.NoError(t, sub.Error())
+		defer func() { _ = sub.Close() }()
+
+		mqttClient.publish(nodeclient.EventAPIBlocksConfirmed, blockBytes)
+		mqttClient.publish(nodeclient.EventAPIBlocksConfirmed, blockBytes)
+
+		requireReceived(t, slowBlocks)
+		requireClosed(t, slowBlocks)
+		require.NoError(t, slowSub.Close())
+
+		requireReceived(t, blocks)
+		requireReceived(t, blocks)
+
+		_, unsubscribes := mqttClient.counts(nodeclient.EventAPIBlocksConfirmed)
+		require.Equal(t, 0, unsubscribes)
+	})
+
+	t.Run("slow consumer policy block", func(t *testing.T) {
+		blocks, sub := eventAPIClient.TransactionBlocks()
+		require.NoError(t, sub.Error())
+
+		go mqttClient.publish(nodeclient.EventAPIBlocksTransaction, blockBytes)
+		select {
+		case received := <-blocks:
+			require.Equal(t, block.MustID(), received.MustID())
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "block not received")
+		}
+
+		// closing the subscription aborts blocked deliveries
+		published := make(chan struct{})
+		go func() {
+			mqttClient.publish(nodeclient.EventAPIBlocksTransaction, blockBytes)
+			close(published)
+		}()
+		require.NoError(t, sub.Close())
+
+		select {
+		case <-published:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "delivery not aborted")
+		}
+	})
+}
+
+// replayingMqttClient is a reconnectingMqttClient which synchronously delivers a payload to the handler when a topic is resubscribed.
+type replayingMqttClient struct {
+	*reconnectingMqttClient
+	payload []byte
+}
+
+func (m *replayingMqttClient) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	token := m.reconnectingMqttClient.Subscribe(topic, qos, callback)
+
+	if m.subscriptionCount(topic) > 1 {
+		callback(m, &mockMsg{payload: m.payload})
+	}
+
+	return token
+}
+
+func Test_EventAPIClientResubscribeDelivery(t *testing.T) {
+	defer gock.Off()
+
+	block := tpkg.RandBlock(tpkg.RandBasicBlockBody(tpkg.ZeroCostTestAPI, iotago.PayloadTaggedData), tpkg.ZeroCostTestAPI, 0)
+	blockBytes, err := tpkg.ZeroCostTestAPI.Encode(block)
+	require.NoError(t, err)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	mockGetJSON(api.RouteRoutes, 200, &api.RoutesResponse{Routes: []iotago.PrefixedStringUint8{api.MQTTPluginName}})
+
+	reconnected := make(chan struct{}, 1)
+	eventAPIClient, err := nodeClient(t).EventAPI(ctx,
+		nodeclient.WithEventAPIReconnectBackoff(time.Millisecond, 5*time.Millisecond),
+		nodeclient.WithEventAPIConnectionCheckInterval(10*time.Millisecond),
+		nodeclient.WithEventAPIConnectionStateCallback(func(state nodeclient.EventAPIConnectionState, _ error) {
+			if state == nodeclient.EventAPIConnectionStateConnected {
+				reconnected <- struct{}{}
+			}
+		}),
+	)
+	require.NoError(t, err)
+
+	mqttClient := &replayingMqttClient{
+		reconnectingMqttClient: &reconnectingMqttClient{subscriptions: make(map[string]int)},
+		payload:                blockBytes,
+	}
+	eventAPIClient.MQTTClient = mqttClient
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	blocks, sub := eventAPIClient.Blocks(nodeclient.WithSubscriptionBufferSize(1))
+	require.NoError(t, sub.Error())
+
+	// the event is delivered while the subscription is restored
+	mqttClient.dropConnection(0)
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "subscription not restored")
+	}
+	require.Equal(t, block.MustID(), requireReceived(t, blocks).MustID())
+}
+
+// disconnectingMqttClient is a brokerMqttClient which can be disconnected.
+type disconnectingMqttClient struct {
+	*brokerMqttClient
+}
+
+func (m *disconnectingMqttClient) Disconnect(_ uint) {}
+
+func Test_EventAPIClientClose(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	eventAPIClient := &nodeclient.EventAPIClient{
+		Client:     nodeClient(t),
+		MQTTClient: &disconnectingMqttClient{brokerMqttClient: newBrokerMqttClient()},
+		Errors:     make(chan error),
+	}
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	blocks1, sub1 := eventAPIClient.Blocks()
+	require.NoError(t, sub1.Error())
+	blocks2, sub2 := eventAPIClient.Blocks()
+	require.NoError(t, sub2.Error())
+	commitments, sub3 := eventAPIClient.Commitments()
+	require.NoError(t, sub3.Error())
+
+	eventAPIClient.Close()
+
+	requireClosed(t, blocks1)
+	requireClosed(t, blocks2)
+	requireClosed(t, commitments)
+
+	// closing the subscriptions afterwards is a no-op
+	require.NoError(t, sub1.Close())
+	require.NoError(t, sub3.Close())
+}
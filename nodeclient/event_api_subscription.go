@@ -0,0 +1,260 @@
+package nodeclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/iotaledger/hive.go/ierrors"
+)
+
+var (
+	// ErrEventAPISlowConsumer gets sent to the Errors channel of an EventAPIClient
+	// when an event was dropped or a subscription was closed because the subscriber didn't keep up.
+	ErrEventAPISlowConsumer = ierrors.New("event api subscriber is too slow")
+)
+
+// SlowConsumerPolicy defines what happens if the channel of a subscriber is full when an event arrives.
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerPolicyBlock waits until the subscriber received the event.
+	// This also delays the delivery of the event to all other subscribers of the topic.
+	SlowConsumerPolicyBlock SlowConsumerPolicy = iota
+	// SlowConsumerPolicyDrop drops the event for the subscriber.
+	SlowConsumerPolicyDrop
+	// SlowConsumerPolicyClose closes the subscription of the subscriber.
+	SlowConsumerPolicyClose
+)
+
+func (p SlowConsumerPolicy) String() string {
+	switch p {
+	case SlowConsumerPolicyBlock:
+		return "block"
+	case SlowConsumerPolicyDrop:
+		return "drop"
+	case SlowConsumerPolicyClose:
+		return "close"
+	default:
+		return fmt.Sprintf("unknown slow consumer policy (%d)", p)
+	}
+}
+
+// SubscriptionOptions define options for a subscription of an EventAPIClient.
+type SubscriptionOptions struct {
+	// The size of the buffer of the channel of the subscriber.
+	bufferSize int
+	// What happens if the buffer of the subscriber is full.
+	slowConsumerPolicy SlowConsumerPolicy
+}
+
+// SubscriptionOption is a function setting a subscription option.
+type SubscriptionOption func(opts *SubscriptionOptions)
+
+// WithSubscriptionBufferSize sets the size of the buffer of the channel of the subscriber.
+func WithSubscriptionBufferSize(bufferSize int) SubscriptionOption {
+	return func(opts *SubscriptionOptions) {
+		opts.bufferSize = bufferSize
+	}
+}
+
+// WithSlowConsumerPolicy sets what happens if the buffer of the subscriber is full when an event arrives.
+func WithSlowConsumerPolicy(policy SlowConsumerPolicy) SubscriptionOption {
+	return func(opts *SubscriptionOptions) {
+		opts.slowConsumerPolicy = policy
+	}
+}
+
+// eventAPISubscriber is a subscriber of a topic of an EventAPIClient.
+type eventAPISubscriber interface {
+	// deliver sends the event to the subscriber and returns false if the subscription should be closed.
+	deliver(ctx context.Context, event any) bool
+	// close closes the channel of the subscriber.
+	close()
+}
+
+// subscriber is an eventAPISubscriber which receives the events on a channel.
+type subscriber[T any] struct {
+	channel chan T
+	policy  SlowConsumerPolicy
+	// closed when the subscriber is closed to abort blocking deliveries.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// guards the channel against being closed during a delivery.
+	mutex  sync.RWMutex
+	closed bool
+}
+
+func newSubscriber[T any](opts *SubscriptionOptions) *subscriber[T] {
+	return &subscriber[T]{
+		channel: make(chan T, opts.bufferSize),
+		policy:  opts.slowConsumerPolicy,
+		done:    make(chan struct{}),
+	}
+}
+
+func (s *subscriber[T]) deliver(ctx context.Context, event any) bool {
+	obj, ok := event.(T)
+	if !ok {
+		return true
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return true
+	}
+
+	switch s.policy {
+	case SlowConsumerPolicyDrop:
+		select {
+		case s.channel <- obj:
+		default:
+		}
+
+		return true
+	case SlowConsumerPolicyClose:
+		select {
+		case s.channel <- obj:
+			return true
+		default:
+			return false
+		}
+	default:
+		select {
+		case <-ctx.Done():
+		case <-s.done:
+		case s.channel <- obj:
+		}
+
+		return true
+	}
+}
+
+func (s *subscriber[T]) close() {
+	s.closeOnce.Do(func() {
+		// abort blocking deliveries before the channel is closed
+		close(s.done)
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.closed = true
+		close(s.channel)
+	})
+}
+
+// topicSubscription is the MQTT subscription of a topic shared by all subscribers of the topic.
+type topicSubscription struct {
+	handler     mqtt.MessageHandler
+	subscribers []eventAPISubscriber
+}
+
+func subscribeToTopic[T any](eac *EventAPIClient, topic string, deseriFunc func(payload []byte) (T, error), opts ...SubscriptionOption) (<-chan T, *EventAPIClientSubscription) {
+	panicIfEventAPIClientInactive(eac)
+
+	subscriptionOpts := &SubscriptionOptions{}
+	for _, opt := range opts {
+		opt(subscriptionOpts)
+	}
+	sub := newSubscriber[T](subscriptionOpts)
+
+	eac.subscriptionsMutex.Lock()
+	defer eac.subscriptionsMutex.Unlock()
+
+	if eac.subscriptions == nil {
+		eac.subscriptions = make(map[string]*topicSubscription)
+	}
+
+	// the topic is already subscribed, the events are shared with the other subscribers
+	if topicSub, exists := eac.subscriptions[topic]; exists {
+		topicSub.subscribers = append(topicSub.subscribers, sub)
+
+		return sub.channel, newSubscription(eac, topic, sub)
+	}
+
+	topicSub := &topicSubscription{
+		handler: func(client mqtt.Client, mqttMsg mqtt.Message) {
+			obj, err := deseriFunc(mqttMsg.Payload())
+			if err != nil {
+				sendErrOrDrop(eac.Errors, err)
+
+				return
+			}
+
+			eac.broadcast(topic, obj)
+		},
+		subscribers: []eventAPISubscriber{sub},
+	}
+	eac.subscriptions[topic] = topicSub
+
+	if token := eac.MQTTClient.Subscribe(topic, 2, topicSub.handler); token.Wait() && token.Error() != nil {
+		delete(eac.subscriptions, topic)
+
+		return nil, newSubscriptionWithError(token.Error())
+	}
+
+	return sub.channel, newSubscription(eac, topic, sub)
+}
+
+// broadcast delivers the event to all subscribers of the topic.
+func (eac *EventAPIClient) broadcast(topic string, event any) {
+	eac.subscriptionsMutex.Lock()
+	var subscribers []eventAPISubscriber
+	if topicSub, exists := eac.subscriptions[topic]; exists {
+		subscribers = append(subscribers, topicSub.subscribers...)
+	}
+	eac.subscriptionsMutex.Unlock()
+
+	for _, sub := range subscribers {
+		if !sub.deliver(eac.ctx, event) {
+			sendErrOrDrop(eac.Errors, ierrors.Wrapf(ErrEventAPISlowConsumer, "subscription to %s closed", topic))
+
+			if err := eac.unsubscribe(topic, sub); err != nil {
+				sendErrOrDrop(eac.Errors, err)
+			}
+		}
+	}
+}
+
+// unsubscribe removes the subscriber from the topic and unsubscribes from the topic if it was the last subscriber.
+func (eac *EventAPIClient) unsubscribe(topic string, sub eventAPISubscriber) error {
+	eac.subscriptionsMutex.Lock()
+
+	topicSub, exists := eac.subscriptions[topic]
+	if !exists {
+		eac.subscriptionsMutex.Unlock()
+
+		return nil
+	}
+
+	for i, topicSubscriber := range topicSub.subscribers {
+		if topicSubscriber == sub {
+			topicSub.subscribers = append(topicSub.subscribers[:i], topicSub.subscribers[i+1:]...)
+			break
+		}
+	}
+
+	lastSubscriber := len(topicSub.subscribers) == 0
+	if lastSubscriber {
+		// the subscription is not restored after a reconnect anymore
+		delete(eac.subscriptions, topic)
+	}
+	eac.subscriptionsMutex.Unlock()
+
+	sub.close()
+
+	if !lastSubscriber {
+		return nil
+	}
+
+	if token := eac.MQTTClient.Unsubscribe(topic); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/iotaledger/hive.go/ierrors"
 	"github.com/iotaledger/hive.go/serializer/v2/serix"
@@ -29,6 +31,17 @@ var (
 	ErrHTTPNotImplemented = ierrors.New("operation not implemented/supported/available")
 	// ErrHTTPServiceUnavailable gets returned for 503 service unavailable error HTTP responses.
 	ErrHTTPServiceUnavailable = ierrors.New("service unavailable")
+	// ErrHTTPTooManyRequests gets returned for 429 too many requests error HTTP responses.
+	ErrHTTPTooManyRequests = ierrors.New("too many requests")
+	// ErrHTTPNotAcceptable gets returned for 406 not acceptable error HTTP responses.
+	ErrHTTPNotAcceptable = ierrors.New("not acceptable")
+
+	// ErrHTTPClientError matches all HTTPErrors with a 4xx status code.
+	ErrHTTPClientError = ierrors.New("client error")
+	// ErrHTTPServerError matches all HTTPErrors with a 5xx status code.
+	ErrHTTPServerError = ierrors.New("server error")
+	// ErrNetwork matches all NetworkErrors.
+	ErrNetwork = ierrors.New("network error")
 
 	httpCodeToErr = map[int]error{
 		http.StatusBadRequest:          ErrHTTPBadRequest,
@@ -37,13 +50,85 @@ var (
 		http.StatusUnauthorized:        ErrHTTPUnauthorized,
 		http.StatusNotImplemented:      ErrHTTPNotImplemented,
 		http.StatusServiceUnavailable:  ErrHTTPServiceUnavailable,
+		http.StatusTooManyRequests:     ErrHTTPTooManyRequests,
+		http.StatusNotAcceptable:       ErrHTTPNotAcceptable,
 	}
 )
 
+// HTTPError gets returned for HTTP responses with an error status code.
+// It matches the error of its status code (e.g. ErrHTTPNotFound) and
+// either ErrHTTPClientError or ErrHTTPServerError depending on the status code.
+type HTTPError struct {
+	// The status code of the response.
+	StatusCode int
+	// The URL of the request.
+	URL string
+	// The error code of the error response.
+	Code string
+	// The error message of the error response, or the raw body if it is not a JSON error response.
+	Message string
+	// The duration to wait before retrying the request as requested by the "Retry-After" header, zero if not set.
+	RetryAfter time.Duration
+
+	err error
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s: url %s, error message: %s", e.err, e.URL, e.Message)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.err
+}
+
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrHTTPClientError:
+		return e.StatusCode >= 400 && e.StatusCode < 500
+	case ErrHTTPServerError:
+		return e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// NetworkError gets returned if a request failed without receiving a response, e.g. because the connection was refused or reset.
+// It matches ErrNetwork.
+type NetworkError struct {
+	// The URL of the request.
+	URL string
+
+	err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%s: url %s: %s", ErrNetwork, e.URL, e.err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.err
+}
+
+func (e *NetworkError) Is(target error) bool {
+	return target == ErrNetwork
+}
+
 const (
-	locationHeader = "Location"
+	locationHeader    = "Location"
+	retryAfterHeader  = "Retry-After"
+	contentTypeHeader = "Content-Type"
 )
 
+// mediaType returns the media type of the given "Content-Type" header without its parameters.
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+
+	return mediaType
+}
+
 func readBody(res *http.Response) ([]byte, error) {
 	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
@@ -69,6 +154,15 @@ func interpretBody(ctx context.Context, serixAPI *serix.API, res *http.Response,
 		if rawData, ok := decodeTo.(*RawDataEnvelope); ok {
 			rawData.Data = make([]byte, len(resBody))
 			copy(rawData.Data, resBody)
+			rawData.ContentType = res.Header.Get(contentTypeHeader)
+
+			return nil
+		}
+
+		if mediaType(res.Header.Get(contentTypeHeader)) == api.MIMEApplicationVendorIOTASerializerV2 {
+			if _, err := serixAPI.Decode(ctx, resBody, decodeTo); err != nil {
+				return ierrors.Errorf("unable to decode binary response: %w", err)
+			}
 
 			return nil
 		}
@@ -84,7 +178,9 @@ func interpretBody(ctx context.Context, serixAPI *serix.API, res *http.Response,
 	errRes := &HTTPErrorResponseEnvelope{}
 	if len(resBody) > 0 {
 		if err := json.Unmarshal(resBody, errRes); err != nil {
-			return ierrors.Errorf("unable to read error from response body: %w", err)
+			// proxies and load balancers answer with plain text or HTML, the body is kept as the message
+			errRes = &HTTPErrorResponseEnvelope{}
+			errRes.Error.Message = string(resBody)
 		}
 	}
 
@@ -93,7 +189,14 @@ func interpretBody(ctx context.Context, serixAPI *serix.API, res *http.Response,
 		err = ErrHTTPUnknownError
 	}
 
-	return ierrors.Wrapf(err, "url %s, error message: %s", res.Request.URL.String(), errRes.Error.Message)
+	return &HTTPError{
+		StatusCode: res.StatusCode,
+		URL:        res.Request.URL.String(),
+		Code:       errRes.Error.Code,
+		Message:    errRes.Error.Message,
+		RetryAfter: parseRetryAfter(res.Header.Get(retryAfterHeader), time.Now()),
+		err:        err,
+	}
 }
 
 func do(
@@ -106,6 +209,7 @@ func do(
 	route string,
 	requestURLHook RequestURLHook,
 	requestHeaderHook RequestHeaderHook,
+	retryPolicy *RetryPolicy,
 	reqObj interface{},
 	resObj interface{}) (*http.Response, error) {
 
@@ -133,8 +237,37 @@ func do(
 		url = requestURLHook(url)
 	}
 
+	for attempt := 0; ; attempt++ {
+		res, err := doAttempt(ctx, serixAPI, httpClient, userInfo, method, url, requestHeaderHook, data, raw, resObj)
+		if err == nil {
+			return res, nil
+		}
+
+		if !retryPolicy.shouldRetry(ctx, method, attempt, err) {
+			return nil, err
+		}
+
+		if err := retryPolicy.wait(ctx, attempt, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doAttempt sends a single request and interprets the response.
+func doAttempt(
+	ctx context.Context,
+	serixAPI *serix.API,
+	httpClient *http.Client,
+	userInfo *url.Userinfo,
+	method string,
+	requestURL string,
+	requestHeaderHook RequestHeaderHook,
+	data []byte,
+	raw bool,
+	resObj interface{}) (*http.Response, error) {
+
 	// construct request
-	req, err := http.NewRequestWithContext(ctx, method, url, func() io.Reader {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, func() io.Reader {
 		if data == nil {
 			return nil
 		}
@@ -152,9 +285,9 @@ func do(
 
 	if data != nil {
 		if !raw {
-			req.Header.Set("Content-Type", api.MIMEApplicationJSON)
+			req.Header.Set(contentTypeHeader, api.MIMEApplicationJSON)
 		} else {
-			req.Header.Set("Content-Type", api.MIMEApplicationVendorIOTASerializerV2)
+			req.Header.Set(contentTypeHeader, api.MIMEApplicationVendorIOTASerializerV2)
 		}
 	}
 
@@ -165,7 +298,11 @@ func do(
 	// make the request
 	res, err := httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		return nil, &NetworkError{URL: requestURL, err: err}
 	}
 
 	// write response into response object
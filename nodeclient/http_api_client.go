@@ -2,13 +2,16 @@ package nodeclient
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
 	"github.com/iotaledger/hive.go/serializer/v2/serix"
 	iotago "github.com/iotaledger/iota.go/v4"
 	"github.com/iotaledger/iota.go/v4/api"
@@ -36,6 +39,11 @@ var (
 	RequestHeaderHookAcceptJSON = func(header http.Header) { header.Set("Accept", api.MIMEApplicationJSON) }
 	// RequestHeaderHookAcceptIOTASerializerV2 is used to set the request "Accept" header to MIMEApplicationVendorIOTASerializerV2.
 	RequestHeaderHookAcceptIOTASerializerV2 = func(header http.Header) { header.Set("Accept", api.MIMEApplicationVendorIOTASerializerV2) }
+	// RequestHeaderHookAcceptIOTASerializerV2OrJSON is used to set the request "Accept" header to MIMEApplicationVendorIOTASerializerV2,
+	// with MIMEApplicationJSON as fallback if the endpoint doesn't support the binary format.
+	RequestHeaderHookAcceptIOTASerializerV2OrJSON = func(header http.Header) {
+		header.Set("Accept", api.MIMEApplicationVendorIOTASerializerV2+", "+api.MIMEApplicationJSON+";q=0.9")
+	}
 	// RequestHeaderHookContentTypeIOTASerializerV2 is used to set the request "Content-Type" header to MIMEApplicationVendorIOTASerializerV2.
 	RequestHeaderHookContentTypeIOTASerializerV2 = func(header http.Header) { header.Set("Content-Type", api.MIMEApplicationVendorIOTASerializerV2) }
 )
@@ -55,6 +63,16 @@ type ClientOptions struct {
 	userInfo *url.Userinfo
 	// The hook to modify the URL before sending a request.
 	requestURLHook RequestURLHook
+	// The policy used to retry failed requests.
+	retryPolicy *RetryPolicy
+	// Whether the responses are requested in the binary format.
+	binaryResponses bool
+	// The cache for the responses of immutable node objects.
+	responseCache ResponseCache
+	// The callback called when a new protocol version becomes active.
+	protocolVersionActivatedCallback ProtocolVersionActivatedCallback
+	// The callback creating the API of unknown protocol versions.
+	apiForMissingVersionCallback func(protocolParameters iotago.ProtocolParameters) (iotago.API, error)
 }
 
 // applies the given ClientOption.
@@ -85,6 +103,15 @@ func WithRequestURLHook(requestURLHook RequestURLHook) ClientOption {
 	}
 }
 
+// WithBinaryResponses sets whether the responses of all endpoints are requested and decoded in the
+// MIMEApplicationVendorIOTASerializerV2 format, which is cheaper to decode than JSON.
+// Endpoints which don't support the binary format are answered in JSON.
+func WithBinaryResponses(enabled bool) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.binaryResponses = enabled
+	}
+}
+
 // ClientOption is a function setting a Client option.
 type ClientOption func(opts *ClientOptions)
 
@@ -94,16 +121,7 @@ const initInfoEndpointCallTimeout = 5 * time.Second
 // This constructor will automatically call Client.Info() in order to initialize the Client
 // with the appropriate protocol parameters and latest iotago.API version (use WithIOTAGoAPI() to override this behavior).
 func New(baseURL string, opts ...ClientOption) (*Client, error) {
-
-	options := &ClientOptions{}
-	options.apply(defaultNodeAPIOptions...)
-	options.apply(opts...)
-
-	client := &Client{
-		BaseURL:     baseURL,
-		apiProvider: iotago.NewEpochBasedProvider(),
-		opts:        options,
-	}
+	client := newClient(baseURL, opts...)
 
 	ctx, cancelFunc := context.WithTimeout(context.Background(), initInfoEndpointCallTimeout)
 	defer cancelFunc()
@@ -111,13 +129,25 @@ func New(baseURL string, opts ...ClientOption) (*Client, error) {
 	if err != nil {
 		return nil, ierrors.Errorf("unable to call info endpoint for protocol parameter init: %w", err)
 	}
-	for _, params := range info.ProtocolParameters {
-		client.apiProvider.AddProtocolParametersAtEpoch(params.Parameters, params.StartEpoch)
-	}
+	client.addProtocolParameters(info)
 
 	return client, nil
 }
 
+// newClient returns a new Client using the given base URL without initializing the protocol parameters.
+func newClient(baseURL string, opts ...ClientOption) *Client {
+	options := &ClientOptions{}
+	options.apply(defaultNodeAPIOptions...)
+	options.apply(opts...)
+
+	return &Client{
+		BaseURL:       baseURL,
+		apiProvider:   newAPIProvider(options),
+		responseCache: newResponseCache(options.responseCache),
+		opts:          options,
+	}
+}
+
 // Client is a client for node HTTP REST API endpoints.
 type Client struct {
 	// The base URL for all API calls.
@@ -125,6 +155,13 @@ type Client struct {
 
 	apiProvider *iotago.EpochBasedProvider
 
+	// nil if no ResponseCache is set.
+	responseCache *responseCache
+
+	// the version of the committed API the ProtocolVersionActivatedCallback was last checked with.
+	protocolVersion      iotago.Version
+	protocolVersionMutex sync.Mutex
+
 	// holds the Client options.
 	opts *ClientOptions
 }
@@ -141,6 +178,13 @@ type HTTPErrorResponseEnvelope struct {
 type RawDataEnvelope struct {
 	// The encapsulated binary data.
 	Data []byte
+	// The "Content-Type" header of the response the data was received with.
+	ContentType string
+}
+
+// ResponseCacheMetrics returns the metrics of the ResponseCache, which are zero if no ResponseCache is set.
+func (client *Client) ResponseCacheMetrics() ResponseCacheMetrics {
+	return client.responseCache.metrics()
 }
 
 // HTTPClient returns the underlying HTTP client.
@@ -151,13 +195,31 @@ func (client *Client) HTTPClient() *http.Client {
 // Do executes a request against the endpoint.
 // This function is only meant to be used for special routes not covered through the standard API.
 func (client *Client) Do(ctx context.Context, method string, route string, reqObj interface{}, resObj interface{}) (*http.Response, error) {
-	return do(ctx, client.CommittedAPI().Underlying(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, method, route, client.opts.requestURLHook, nil, reqObj, resObj)
+	return client.DoWithRequestHeaderHook(ctx, method, route, nil, reqObj, resObj)
 }
 
 // DoWithRequestHeaderHook executes a request against the endpoint.
 // This function is only meant to be used for special routes not covered through the standard API.
+// If binary responses are enabled and no hook is given, the response is requested in the binary format.
 func (client *Client) DoWithRequestHeaderHook(ctx context.Context, method string, route string, requestHeaderHook RequestHeaderHook, reqObj interface{}, resObj interface{}) (*http.Response, error) {
-	return do(ctx, client.CommittedAPI().Underlying(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, method, route, client.opts.requestURLHook, requestHeaderHook, reqObj, resObj)
+	if requestHeaderHook == nil && client.opts.binaryResponses && resObj != nil {
+		if _, isRawData := resObj.(*RawDataEnvelope); !isRawData {
+			return client.doPreferBinary(ctx, method, route, reqObj, resObj)
+		}
+	}
+
+	return do(ctx, client.CommittedAPI().Underlying(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, method, route, client.opts.requestURLHook, requestHeaderHook, client.opts.retryPolicy, reqObj, resObj)
+}
+
+// doPreferBinary requests the response in the binary format and repeats the request
+// with JSON if the node refused to answer in the binary format.
+func (client *Client) doPreferBinary(ctx context.Context, method string, route string, reqObj interface{}, resObj interface{}) (*http.Response, error) {
+	res, err := do(ctx, client.CommittedAPI().Underlying(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, method, route, client.opts.requestURLHook, RequestHeaderHookAcceptIOTASerializerV2OrJSON, client.opts.retryPolicy, reqObj, resObj)
+	if err == nil || !ierrors.Is(err, ErrHTTPNotAcceptable) {
+		return res, err
+	}
+
+	return do(ctx, client.CommittedAPI().Underlying(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, method, route, client.opts.requestURLHook, RequestHeaderHookAcceptJSON, client.opts.retryPolicy, reqObj, resObj)
 }
 
 // Management returns the ManagementClient.
@@ -190,7 +252,7 @@ func (client *Client) Indexer(ctx context.Context) (IndexerClient, error) {
 
 // EventAPI returns the EventAPIClient if supported by the node.
 // Returns ErrMQTTPluginNotAvailable if the current node does not support the plugin.
-func (client *Client) EventAPI(ctx context.Context) (*EventAPIClient, error) {
+func (client *Client) EventAPI(ctx context.Context, opts ...options.Option[EventAPIClient]) (*EventAPIClient, error) {
 	hasPlugin, err := client.NodeSupportsRoute(ctx, api.MQTTPluginName)
 	if err != nil {
 		return nil, err
@@ -199,7 +261,7 @@ func (client *Client) EventAPI(ctx context.Context) (*EventAPIClient, error) {
 		return nil, ErrMQTTPluginNotAvailable
 	}
 
-	return newEventAPIClient(client), nil
+	return newEventAPIClient(client, opts...), nil
 }
 
 // BlockIssuer returns the BlockIssuerClient.
@@ -218,8 +280,9 @@ func (client *Client) BlockIssuer(ctx context.Context) (BlockIssuerClient, error
 
 // Health returns whether the given node is healthy.
 func (client *Client) Health(ctx context.Context) (bool, error) {
+	// the health check is not retried, as an unavailable service is the answer to the request
 	//nolint:bodyclose
-	if _, err := client.Do(ctx, http.MethodGet, api.RouteHealth, nil, nil); err != nil {
+	if _, err := do(ctx, client.CommittedAPI().Underlying(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, http.MethodGet, api.RouteHealth, client.opts.requestURLHook, nil, nil, nil, nil); err != nil {
 		if ierrors.Is(err, ErrHTTPServiceUnavailable) {
 			return false, nil
 		}
@@ -247,11 +310,11 @@ func (client *Client) Info(ctx context.Context) (*api.InfoResponse, error) {
 	res := new(api.InfoResponse)
 
 	//nolint:bodyclose
-	if _, err := do(ctx, iotago.CommonSerixAPI(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, http.MethodGet, api.CoreRouteInfo, client.opts.requestURLHook, nil, nil, res); err != nil {
+	if _, err := do(ctx, iotago.CommonSerixAPI(), client.opts.httpClient, client.BaseURL, client.opts.userInfo, http.MethodGet, api.CoreRouteInfo, client.opts.requestURLHook, nil, client.opts.retryPolicy, nil, res); err != nil {
 		return nil, err
 	}
 
-	client.apiProvider.SetCommittedSlot(res.Status.LatestCommitmentID.Slot())
+	client.setCommittedSlot(res.Status.LatestCommitmentID.Slot())
 
 	return res, nil
 }
@@ -358,6 +421,7 @@ func (client *Client) NodeSupportsRoute(ctx context.Context, route string) (bool
 // The node will take care of filling missing information.
 // This function returns the blockID of the finalized block.
 // To get the finalized block you need to call "BlockByBlockID".
+// The submission is only retried by the RetryPolicy if the node did not receive the block for sure.
 func (client *Client) SubmitBlock(ctx context.Context, m *iotago.Block) (iotago.BlockID, error) {
 	// do not check the block because the validation would fail if
 	// no parents were given. The node will first add this missing information and
@@ -402,38 +466,56 @@ func (client *Client) BlockMetadataByBlockID(ctx context.Context, blockID iotago
 }
 
 // BlockByBlockID get a block by its block ID from the node.
+// The block is cached if a ResponseCache is set.
 func (client *Client) BlockByBlockID(ctx context.Context, blockID iotago.BlockID) (*iotago.Block, error) {
 	query := client.endpointReplaceBlockIDParameter(api.CoreRouteBlock, blockID)
 
+	return client.cachedBlock(ctx, responseCacheKeyBlock+blockID.ToHex(), query)
+}
+
+// TransactionIncludedBlock get a block that included the given transaction ID in the ledger.
+// The block is not cached, as the transaction might get included in another block.
+func (client *Client) TransactionIncludedBlock(ctx context.Context, txID iotago.TransactionID) (*iotago.Block, error) {
+	query := client.endpointReplaceTransactionIDParameter(api.CoreRouteTransactionsIncludedBlock, txID)
+
 	res := new(RawDataEnvelope)
 	//nolint:bodyclose
-	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2, nil, res); err != nil {
-		return nil, err
-	}
-
-	block, _, err := iotago.BlockFromBytes(client)(res.Data)
-	if err != nil {
+	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2OrJSON, nil, res); err != nil {
 		return nil, err
 	}
 
-	return block, nil
+	return client.decodeBlock(res)
 }
 
-// TransactionIncludedBlock get a block that included the given transaction ID in the ledger.
-func (client *Client) TransactionIncludedBlock(ctx context.Context, txID iotago.TransactionID) (*iotago.Block, error) {
-	query := client.endpointReplaceTransactionIDParameter(api.CoreRouteTransactionsIncludedBlock, txID)
+// cachedBlock returns the block stored in the ResponseCache under the given key,
+// or gets the block from the node and stores it in the ResponseCache.
+// The cached blocks are decoded with the API of their protocol version.
+func (client *Client) cachedBlock(ctx context.Context, cacheKey string, query string) (*iotago.Block, error) {
+	if data, exists := client.responseCache.get(cacheKey); exists {
+		if block, _, err := iotago.BlockFromBytes(client)(data); err == nil {
+			return block, nil
+		}
+	}
 
 	res := new(RawDataEnvelope)
 	//nolint:bodyclose
-	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2, nil, res); err != nil {
+	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2OrJSON, nil, res); err != nil {
 		return nil, err
 	}
 
-	block, _, err := iotago.BlockFromBytes(client)(res.Data)
+	block, err := client.decodeBlock(res)
 	if err != nil {
 		return nil, err
 	}
 
+	client.responseCache.set(cacheKey, func() ([]byte, error) {
+		if mediaType(res.ContentType) != api.MIMEApplicationJSON {
+			return res.Data, nil
+		}
+
+		return block.API.Encode(block)
+	})
+
 	return block, nil
 }
 
@@ -464,30 +546,60 @@ func (client *Client) TransactionMetadata(ctx context.Context, txID iotago.Trans
 }
 
 // OutputByID gets an output by its ID from the node.
+// The output is cached if a ResponseCache is set.
 func (client *Client) OutputByID(ctx context.Context, outputID iotago.OutputID) (iotago.Output, error) {
+	// the response is decoded with the committed API, so the cached response can only be decoded with the same API
+	cacheAPI := client.CommittedAPI()
+	cacheKey := versionedResponseCacheKey(responseCacheKeyOutput, cacheAPI.Version(), outputID.ToHex())
+	if data, exists := client.responseCache.get(cacheKey); exists {
+		var outputResponse api.OutputResponse
+		if _, err := cacheAPI.Decode(data, &outputResponse, serix.WithValidation()); err == nil {
+			if err := verifyOutputID(outputID, outputResponse.Output, outputResponse.OutputIDProof); err == nil {
+				return outputResponse.Output, nil
+			}
+		}
+	}
+
 	query := client.endpointReplaceOutputIDParameter(api.CoreRouteOutput, outputID)
 
 	res := new(RawDataEnvelope)
 	//nolint:bodyclose
-	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2, nil, res); err != nil {
+	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2OrJSON, nil, res); err != nil {
 		return nil, err
 	}
 
 	var outputResponse api.OutputResponse
-	if _, err := client.CommittedAPI().Decode(res.Data, &outputResponse, serix.WithValidation()); err != nil {
+	if err := client.decodeRawData(res, &outputResponse); err != nil {
 		return nil, err
 	}
 
-	derivedOutputID, err := outputResponse.OutputIDProof.OutputID(outputResponse.Output)
-	if err != nil {
+	if err := verifyOutputID(outputID, outputResponse.Output, outputResponse.OutputIDProof); err != nil {
 		return nil, err
 	}
 
+	client.responseCache.set(cacheKey, func() ([]byte, error) {
+		if mediaType(res.ContentType) != api.MIMEApplicationJSON {
+			return res.Data, nil
+		}
+
+		return cacheAPI.Encode(&outputResponse)
+	})
+
+	return outputResponse.Output, nil
+}
+
+// verifyOutputID checks that the output ID derived from the output and its proof matches the given output ID.
+func verifyOutputID(outputID iotago.OutputID, output iotago.Output, outputIDProof *iotago.OutputIDProof) error {
+	derivedOutputID, err := outputIDProof.OutputID(output)
+	if err != nil {
+		return err
+	}
+
 	if derivedOutputID != outputID {
-		return nil, ierrors.Errorf("output ID mismatch. Expected %s, got %s", outputID.ToHex(), derivedOutputID.ToHex())
+		return ierrors.Errorf("output ID mismatch. Expected %s, got %s", outputID.ToHex(), derivedOutputID.ToHex())
 	}
 
-	return outputResponse.Output, nil
+	return nil
 }
 
 // OutputWithMetadataByID gets an output by its ID, together with the metadata from the node.
@@ -496,24 +608,19 @@ func (client *Client) OutputWithMetadataByID(ctx context.Context, outputID iotag
 
 	res := new(RawDataEnvelope)
 	//nolint:bodyclose
-	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2, nil, res); err != nil {
+	if _, err := client.DoWithRequestHeaderHook(ctx, http.MethodGet, query, RequestHeaderHookAcceptIOTASerializerV2OrJSON, nil, res); err != nil {
 		return nil, nil, err
 	}
 
 	var outputResponse api.OutputWithMetadataResponse
-	if _, err := client.CommittedAPI().Decode(res.Data, &outputResponse, serix.WithValidation()); err != nil {
+	if err := client.decodeRawData(res, &outputResponse); err != nil {
 		return nil, nil, err
 	}
 
-	derivedOutputID, err := outputResponse.OutputIDProof.OutputID(outputResponse.Output)
-	if err != nil {
+	if err := verifyOutputID(outputID, outputResponse.Output, outputResponse.OutputIDProof); err != nil {
 		return nil, nil, err
 	}
 
-	if derivedOutputID != outputID {
-		return nil, nil, ierrors.Errorf("output ID mismatch. Expected %s, got %s", outputID.ToHex(), derivedOutputID.ToHex())
-	}
-
 	return outputResponse.Output, outputResponse.Metadata, nil
 }
 
@@ -531,7 +638,17 @@ func (client *Client) OutputMetadataByID(ctx context.Context, outputID iotago.Ou
 }
 
 // CommitmentByID gets a commitment details by its ID.
+// The commitment is cached if a ResponseCache is set.
 func (client *Client) CommitmentByID(ctx context.Context, commitmentID iotago.CommitmentID) (*iotago.Commitment, error) {
+	cacheAPI := client.CommittedAPI()
+	cacheKey := versionedResponseCacheKey(responseCacheKeyCommitment, cacheAPI.Version(), commitmentID.ToHex())
+	if data, exists := client.responseCache.get(cacheKey); exists {
+		res := new(iotago.Commitment)
+		if _, err := cacheAPI.Decode(data, res, serix.WithValidation()); err == nil {
+			return res, nil
+		}
+	}
+
 	query := client.endpointReplaceCommitmentIDParameter(api.CoreRouteCommitmentByID, commitmentID)
 
 	res := new(iotago.Commitment)
@@ -540,6 +657,10 @@ func (client *Client) CommitmentByID(ctx context.Context, commitmentID iotago.Co
 		return nil, err
 	}
 
+	client.responseCache.set(cacheKey, func() ([]byte, error) {
+		return cacheAPI.Encode(res)
+	})
+
 	return res, nil
 }
 
@@ -608,6 +729,53 @@ func (client *Client) CommitmentUTXOChangesFullByIndex(ctx context.Context, slot
 	return res, nil
 }
 
+// decodeRawData decodes the given data, which was received either in the binary or the JSON format.
+func (client *Client) decodeRawData(rawData *RawDataEnvelope, obj interface{}) error {
+	if mediaType(rawData.ContentType) == api.MIMEApplicationJSON {
+		return client.CommittedAPI().JSONDecode(rawData.Data, obj, serix.WithValidation())
+	}
+
+	if _, err := client.CommittedAPI().Decode(rawData.Data, obj, serix.WithValidation()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// decodeBlock decodes the given block with the API of its protocol version,
+// the block was received either in the binary or the JSON format.
+func (client *Client) decodeBlock(rawData *RawDataEnvelope) (*iotago.Block, error) {
+	if mediaType(rawData.ContentType) != api.MIMEApplicationJSON {
+		block, _, err := iotago.BlockFromBytes(client)(rawData.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		return block, nil
+	}
+
+	var versionedBlock struct {
+		Header struct {
+			ProtocolVersion iotago.Version `json:"protocolVersion"`
+		} `json:"header"`
+	}
+	if err := json.Unmarshal(rawData.Data, &versionedBlock); err != nil {
+		return nil, ierrors.Wrap(err, "failed to parse the protocol version of the block")
+	}
+
+	apiForVersion, err := client.APIForVersion(versionedBlock.Header.ProtocolVersion)
+	if err != nil {
+		return nil, ierrors.Wrapf(err, "failed to retrieve API for version %d", versionedBlock.Header.ProtocolVersion)
+	}
+
+	block := new(iotago.Block)
+	if err := apiForVersion.JSONDecode(rawData.Data, block, serix.WithValidation()); err != nil {
+		return nil, ierrors.Wrap(err, "failed to deserialize Block")
+	}
+
+	return block, nil
+}
+
 func (client *Client) endpointReplaceAddressParameter(endpoint string, address iotago.Address) string {
 	return api.EndpointWithNamedParameterValue(endpoint, api.ParameterBech32Address, address.Bech32(client.CommittedAPI().ProtocolParameters().Bech32HRP()))
 }
@@ -6,8 +6,10 @@ package nodeclient_test
 import (
 	"bytes"
 	"context"
+	"io"
 	"math"
 	"math/rand"
+	"net/http"
 	"strconv"
 	"testing"
 	"time"
@@ -88,7 +90,7 @@ func mockGetBinary(route string, status int, body interface{}, persist ...bool)
 }
 
 //nolint:thelper
-func nodeClient(t *testing.T) *nodeclient.Client {
+func nodeClient(t *testing.T, opts ...nodeclient.ClientOption) *nodeclient.Client {
 
 	ts := time.Now()
 	originInfo := &api.InfoResponse{
@@ -128,7 +130,7 @@ func nodeClient(t *testing.T) *nodeclient.Client {
 
 	mockGetJSON(api.CoreRouteInfo, 200, originInfo)
 
-	client, err := nodeclient.New(nodeAPIUrl)
+	client, err := nodeclient.New(nodeAPIUrl, opts...)
 	require.NoError(t, err)
 
 	return client
@@ -697,6 +699,204 @@ func TestClient_CommitmentUTXOChangesFullByIndex(t *testing.T) {
 	require.EqualValues(t, originRes, resp)
 }
 
+func TestClient_BinaryResponses(t *testing.T) {
+	defer gock.Off()
+
+	var slot iotago.SlotIndex = 1337
+	route := api.EndpointWithNamedParameterValue(api.CoreRouteCommitmentBySlot, api.ParameterSlot, strconv.Itoa(int(slot)))
+
+	originRes := iotago.NewCommitment(mockAPI.Version(), slot, iotago.NewCommitmentID(slot-1, tpkg.Rand32ByteArray()), tpkg.Rand32ByteArray(), tpkg.RandUint64(math.MaxUint64), tpkg.RandMana(iotago.MaxMana))
+
+	nodeAPI := nodeClient(t, nodeclient.WithBinaryResponses(true))
+
+	t.Run("ok - binary response", func(t *testing.T) {
+		mockGetBinary(route, 200, originRes)
+
+		resp, err := nodeAPI.CommitmentByIndex(context.Background(), slot)
+		require.NoError(t, err)
+		require.EqualValues(t, originRes, resp)
+		require.True(t, gock.IsDone())
+	})
+
+	t.Run("ok - endpoint answers in JSON", func(t *testing.T) {
+		mockGetJSON(route, 200, originRes)
+
+		resp, err := nodeAPI.CommitmentByIndex(context.Background(), slot)
+		require.NoError(t, err)
+		require.EqualValues(t, originRes, resp)
+		require.True(t, gock.IsDone())
+	})
+
+	t.Run("ok - fallback to JSON", func(t *testing.T) {
+		gock.New(nodeAPIUrl).
+			Get(route).
+			MatchHeader("Accept", api.MIMEApplicationVendorIOTASerializerV2).
+			Reply(406).
+			SetHeader("Content-Type", api.MIMEApplicationJSON).
+			BodyString(`{"error":{"code":"406","message":"not acceptable"}}`)
+		gock.New(nodeAPIUrl).
+			Get(route).
+			MatchHeader("Accept", "^"+api.MIMEApplicationJSON+"$").
+			Reply(200).
+			SetHeader("Content-Type", api.MIMEApplicationJSON).
+			BodyString(string(lo.PanicOnErr(mockAPI.JSONEncode(originRes))))
+
+		resp, err := nodeAPI.CommitmentByIndex(context.Background(), slot)
+		require.NoError(t, err)
+		require.EqualValues(t, originRes, resp)
+		require.True(t, gock.IsDone())
+	})
+
+	t.Run("err - not acceptable without binary responses", func(t *testing.T) {
+		gock.New(nodeAPIUrl).
+			Get(route).
+			Reply(406)
+
+		_, err := nodeClient(t).CommitmentByIndex(context.Background(), slot)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPNotAcceptable)
+		require.True(t, gock.IsDone())
+	})
+}
+
+func TestClient_BlockByBlockIDJSON(t *testing.T) {
+	defer gock.Off()
+
+	blockID := tpkg.RandBlockID()
+	originBlock := testBlock()
+
+	// the node doesn't support the binary format
+	mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteBlock, api.ParameterBlockID, blockID.ToHex()), 200, originBlock)
+
+	nodeAPI := nodeClient(t)
+	responseBlock, err := nodeAPI.BlockByBlockID(context.Background(), blockID)
+	require.NoError(t, err)
+	require.EqualValues(t, lo.PanicOnErr(originBlock.ID()), lo.PanicOnErr(responseBlock.ID()))
+}
+
+func TestClient_OutputByIDJSON(t *testing.T) {
+	defer gock.Off()
+
+	originOutput := tpkg.RandBasicOutput(iotago.AddressEd25519)
+
+	originOutputProof, err := iotago.NewOutputIDProof(tpkg.ZeroCostTestAPI, tpkg.Rand32ByteArray(), tpkg.RandSlot(), iotago.TxEssenceOutputs{originOutput}, 0)
+	require.NoError(t, err)
+
+	outputID, err := originOutputProof.OutputID(originOutput)
+	require.NoError(t, err)
+
+	// the node doesn't support the binary format
+	mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteOutput, api.ParameterOutputID, outputID.ToHex()), 200, &api.OutputResponse{
+		Output:        originOutput,
+		OutputIDProof: originOutputProof,
+	})
+
+	nodeAPI := nodeClient(t)
+	responseOutput, err := nodeAPI.OutputByID(context.Background(), outputID)
+	require.NoError(t, err)
+	require.EqualValues(t, originOutput, responseOutput)
+}
+
+// roundTripperFunc answers requests without a network connection.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// benchmarkNodeClient returns a Client for a node which answers all requests except the info route
+// with the given object, encoded with the given content type.
+func benchmarkNodeClient(b *testing.B, contentType string, obj interface{}) *nodeclient.Client {
+	b.Helper()
+
+	info := lo.PanicOnErr(mockAPI.JSONEncode(&api.InfoResponse{
+		Name:    "benchmark",
+		Version: "1.0.0",
+		Status:  &api.InfoResNodeStatus{IsHealthy: true},
+		ProtocolParameters: []*api.InfoResProtocolParameters{
+			{StartEpoch: 0, Parameters: tpkg.IOTAMainnetV3TestProtocolParameters},
+		},
+		BaseToken: &api.InfoResBaseToken{Name: "TestCoin", TickerSymbol: "TEST", Unit: "TEST", Decimals: 6},
+		Metrics:   &api.InfoResNodeMetrics{},
+	}))
+
+	var body []byte
+	if contentType == api.MIMEApplicationJSON {
+		body = lo.PanicOnErr(mockAPI.JSONEncode(obj))
+	} else {
+		body = lo.PanicOnErr(mockAPI.Encode(obj))
+	}
+
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			res := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Request:    req,
+			}
+
+			if req.URL.Path == api.CoreRouteInfo {
+				res.Header.Set("Content-Type", api.MIMEApplicationJSON)
+				res.Body = io.NopCloser(bytes.NewReader(info))
+
+				return res, nil
+			}
+
+			res.Header.Set("Content-Type", contentType)
+			res.Body = io.NopCloser(bytes.NewReader(body))
+
+			return res, nil
+		}),
+	}
+
+	client, err := nodeclient.New(nodeAPIUrl, nodeclient.WithHTTPClient(httpClient))
+	require.NoError(b, err)
+
+	return client
+}
+
+func BenchmarkClient_BlockByBlockID(b *testing.B) {
+	block := testBlock()
+	block.Body.(*iotago.BasicBlockBody).StrongParents = tpkg.SortedRandBlockIDs(iotago.BasicBlockMaxParents)
+	blockID := lo.PanicOnErr(block.ID())
+
+	for _, contentType := range []string{api.MIMEApplicationVendorIOTASerializerV2, api.MIMEApplicationJSON} {
+		b.Run(contentType, func(b *testing.B) {
+			nodeAPI := benchmarkNodeClient(b, contentType, block)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := nodeAPI.BlockByBlockID(context.Background(), blockID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkClient_OutputByID(b *testing.B) {
+	output := tpkg.RandBasicOutput(iotago.AddressEd25519)
+	outputIDProof := lo.PanicOnErr(iotago.NewOutputIDProof(mockAPI, tpkg.Rand32ByteArray(), tpkg.RandSlot(), iotago.TxEssenceOutputs{output}, 0))
+	outputID := lo.PanicOnErr(outputIDProof.OutputID(output))
+
+	for _, contentType := range []string{api.MIMEApplicationVendorIOTASerializerV2, api.MIMEApplicationJSON} {
+		b.Run(contentType, func(b *testing.B) {
+			nodeAPI := benchmarkNodeClient(b, contentType, &api.OutputResponse{
+				Output:        output,
+				OutputIDProof: outputIDProof,
+			})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := nodeAPI.OutputByID(context.Background(), outputID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 var sampleGossipInfo = &api.GossipInfo{
 	Heartbeat: &api.GossipHeartbeat{
 		SolidSlot:      234,
@@ -0,0 +1,278 @@
+package nodeclient
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+)
+
+const (
+	// DefaultLedgerFeedPollInterval is the default interval in which the latest commitment is polled
+	// if the EventAPIClient is not available or a request failed temporarily.
+	DefaultLedgerFeedPollInterval = 2 * time.Second
+)
+
+var (
+	// ErrLedgerFeedUnexpectedSlot gets returned when the node returned the UTXO changes of another slot than requested.
+	ErrLedgerFeedUnexpectedSlot = ierrors.New("node returned the ledger changes of an unexpected slot")
+)
+
+// LedgerUpdate holds the ledger changes of a committed slot.
+type LedgerUpdate struct {
+	// The slot of the changes.
+	Slot iotago.SlotIndex
+	// The changes of the slot.
+	*api.UTXOChangesFullResponse
+}
+
+// LedgerUpdateHandler handles the ledger update of a slot.
+// If an error is returned, the LedgerFeed stops and the cursor is not moved past the slot.
+type LedgerUpdateHandler func(ctx context.Context, update *LedgerUpdate) error
+
+// LedgerFeedCursorStore persists the cursor of a LedgerFeed, which is the last slot whose ledger update was handled.
+type LedgerFeedCursorStore interface {
+	// LoadCursor returns the last handled slot and whether a cursor was stored.
+	LoadCursor() (iotago.SlotIndex, bool, error)
+	// StoreCursor stores the last handled slot.
+	StoreCursor(slot iotago.SlotIndex) error
+}
+
+// FileLedgerFeedCursorStore is a LedgerFeedCursorStore which persists the cursor in a file.
+type FileLedgerFeedCursorStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFileLedgerFeedCursorStore returns a new FileLedgerFeedCursorStore which persists the cursor in the file at the given path.
+func NewFileLedgerFeedCursorStore(path string) *FileLedgerFeedCursorStore {
+	return &FileLedgerFeedCursorStore{path: path}
+}
+
+// LoadCursor returns the cursor stored in the file, false if the file doesn't exist.
+func (s *FileLedgerFeedCursorStore) LoadCursor() (iotago.SlotIndex, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, false, nil
+		}
+
+		return 0, false, ierrors.Wrap(err, "failed to read the cursor file")
+	}
+
+	slot, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, false, ierrors.Wrap(err, "failed to parse the cursor file")
+	}
+
+	return iotago.SlotIndex(slot), true, nil
+}
+
+// StoreCursor atomically replaces the cursor stored in the file.
+func (s *FileLedgerFeedCursorStore) StoreCursor(slot iotago.SlotIndex) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tmpPath := s.path + ".tmp"
+	//nolint:gosec // the cursor is not sensitive
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatUint(uint64(slot), 10)), 0o644); err != nil {
+		return ierrors.Wrap(err, "failed to write the cursor file")
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return ierrors.Wrap(err, "failed to replace the cursor file")
+	}
+
+	return nil
+}
+
+// WithLedgerFeedEventAPIClient sets the EventAPIClient used to get notified about new commitments.
+// The latest commitment is polled if the EventAPIClient is not set or not connected.
+func WithLedgerFeedEventAPIClient(eventAPIClient *EventAPIClient) options.Option[LedgerFeed] {
+	return func(feed *LedgerFeed) {
+		feed.optsEventAPIClient = eventAPIClient
+	}
+}
+
+// WithLedgerFeedCursorStore sets the store used to persist the cursor of the LedgerFeed.
+func WithLedgerFeedCursorStore(cursorStore LedgerFeedCursorStore) options.Option[LedgerFeed] {
+	return func(feed *LedgerFeed) {
+		feed.optsCursorStore = cursorStore
+	}
+}
+
+// WithLedgerFeedPollInterval sets the interval in which the latest commitment is polled.
+func WithLedgerFeedPollInterval(interval time.Duration) options.Option[LedgerFeed] {
+	return func(feed *LedgerFeed) {
+		feed.optsPollInterval = interval
+	}
+}
+
+// LedgerFeed delivers the ledger changes of every committed slot in strict slot order.
+// It backfills the changes of the already committed slots with Client.CommitmentUTXOChangesFullByIndex
+// and afterward follows new commitments, either via the EventAPIClient or by polling the latest commitment.
+type LedgerFeed struct {
+	client *Client
+
+	optsEventAPIClient *EventAPIClient
+	optsCursorStore    LedgerFeedCursorStore
+	optsPollInterval   time.Duration
+}
+
+// NewLedgerFeed returns a new LedgerFeed.
+func NewLedgerFeed(client *Client, opts ...options.Option[LedgerFeed]) *LedgerFeed {
+	return options.Apply(&LedgerFeed{
+		client:           client,
+		optsPollInterval: DefaultLedgerFeedPollInterval,
+	}, opts)
+}
+
+// Run passes the ledger update of every committed slot starting from the given slot to the handler,
+// until the context is done or an error occurs.
+// If a cursor was stored, the LedgerFeed resumes after the cursor instead.
+// The cursor is stored after the handler returned, so an update might be handled again after a restart.
+func (f *LedgerFeed) Run(ctx context.Context, startSlot iotago.SlotIndex, handler LedgerUpdateHandler) error {
+	nextSlot, err := f.resumeSlot(startSlot)
+	if err != nil {
+		return err
+	}
+
+	commitmentInfoChan, subscription := f.subscribe()
+	if subscription != nil {
+		defer func() { _ = subscription.Close() }()
+	}
+
+	pollTicker := time.NewTicker(f.optsPollInterval)
+	defer pollTicker.Stop()
+
+	// the latest slot is unknown until the first successful request
+	latestSlot, err := f.latestCommitmentSlot(ctx)
+	latestSlotKnown := err == nil
+	if err != nil && !isTemporaryError(err) {
+		return err
+	}
+
+	for {
+		for latestSlotKnown && nextSlot <= latestSlot {
+			update, err := f.ledgerUpdate(ctx, nextSlot)
+			if err != nil {
+				if isTemporaryError(err) {
+					// retry after the next poll
+					break
+				}
+
+				return err
+			}
+
+			if err := handler(ctx, update); err != nil {
+				return ierrors.Wrapf(err, "failed to handle the ledger update of slot %d", nextSlot)
+			}
+
+			if f.optsCursorStore != nil {
+				if err := f.optsCursorStore.StoreCursor(nextSlot); err != nil {
+					return err
+				}
+			}
+			nextSlot++
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case commitmentInfo, ok := <-commitmentInfoChan:
+			if !ok {
+				// the subscription was closed, fall back to polling
+				commitmentInfoChan = nil
+				continue
+			}
+
+			if !latestSlotKnown || commitmentInfo.CommitmentSlot > latestSlot {
+				latestSlot, latestSlotKnown = commitmentInfo.CommitmentSlot, true
+			}
+
+		case <-pollTicker.C:
+			slot, err := f.latestCommitmentSlot(ctx)
+			if err != nil {
+				if isTemporaryError(err) {
+					continue
+				}
+
+				return err
+			}
+
+			if !latestSlotKnown || slot > latestSlot {
+				latestSlot, latestSlotKnown = slot, true
+			}
+		}
+	}
+}
+
+// resumeSlot returns the slot after the stored cursor or the given start slot if no cursor was stored.
+func (f *LedgerFeed) resumeSlot(startSlot iotago.SlotIndex) (iotago.SlotIndex, error) {
+	if f.optsCursorStore == nil {
+		return startSlot, nil
+	}
+
+	cursor, exists, err := f.optsCursorStore.LoadCursor()
+	if err != nil {
+		return 0, ierrors.Wrap(err, "failed to load the cursor")
+	}
+	if !exists {
+		return startSlot, nil
+	}
+
+	return cursor + 1, nil
+}
+
+// subscribe subscribes to the latest commitment info if the EventAPIClient is available.
+func (f *LedgerFeed) subscribe() (<-chan *api.CommitmentInfoResponse, *EventAPIClientSubscription) {
+	if f.optsEventAPIClient == nil || !f.optsEventAPIClient.isActive() {
+		return nil, nil
+	}
+
+	// the events only notify about new commitments, so the latest one is sufficient
+	commitmentInfoChan, subscription := f.optsEventAPIClient.LatestCommitmentInfo(WithSubscriptionBufferSize(1), WithSlowConsumerPolicy(SlowConsumerPolicyDrop))
+	if subscription.Error() != nil {
+		return nil, nil
+	}
+
+	return commitmentInfoChan, subscription
+}
+
+func (f *LedgerFeed) latestCommitmentSlot(ctx context.Context) (iotago.SlotIndex, error) {
+	info, err := f.client.Info(ctx)
+	if err != nil {
+		return 0, ierrors.Wrap(err, "failed to get the latest commitment")
+	}
+
+	return info.Status.LatestCommitmentID.Slot(), nil
+}
+
+func (f *LedgerFeed) ledgerUpdate(ctx context.Context, slot iotago.SlotIndex) (*LedgerUpdate, error) {
+	changes, err := f.client.CommitmentUTXOChangesFullByIndex(ctx, slot)
+	if err != nil {
+		return nil, ierrors.Wrapf(err, "failed to get the ledger changes of slot %d", slot)
+	}
+
+	if changes.CommitmentID.Slot() != slot {
+		return nil, ierrors.Wrapf(ErrLedgerFeedUnexpectedSlot, "requested slot %d, got slot %d", slot, changes.CommitmentID.Slot())
+	}
+
+	return &LedgerUpdate{Slot: slot, UTXOChangesFullResponse: changes}, nil
+}
+
+// isTemporaryError returns whether the request might succeed if it is retried later.
+func isTemporaryError(err error) bool {
+	return ierrors.Is(err, ErrNetwork) || ierrors.Is(err, ErrHTTPServerError) || ierrors.Is(err, ErrHTTPTooManyRequests)
+}
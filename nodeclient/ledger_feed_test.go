@@ -0,0 +1,161 @@
+package nodeclient_test
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+func mockUTXOChangesFull(slot iotago.SlotIndex, status int) *api.UTXOChangesFullResponse {
+	changes := &api.UTXOChangesFullResponse{
+		CommitmentID: iotago.NewCommitmentID(slot, tpkg.Rand32ByteArray()),
+		CreatedOutputs: []*api.OutputWithID{
+			{OutputID: tpkg.RandOutputID(0), Output: tpkg.RandBasicOutput(iotago.AddressEd25519)},
+		},
+		ConsumedOutputs: []*api.OutputWithID{},
+	}
+
+	route := api.EndpointWithNamedParameterValue(api.CoreRouteCommitmentBySlotUTXOChangesFull, api.ParameterSlot, strconv.Itoa(int(slot)))
+	if status != 200 {
+		mockGetJSON(route, status, &nodeclient.HTTPErrorResponseEnvelope{})
+		return nil
+	}
+	mockGetJSON(route, status, changes)
+
+	return changes
+}
+
+func TestLedgerFeed(t *testing.T) {
+	defer gock.Off()
+
+	nodeAPI := nodeClient(t)
+	cursorStore := nodeclient.NewFileLedgerFeedCursorStore(filepath.Join(t.TempDir(), "cursor"))
+
+	t.Run("ok - backfill and live", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		mockInfoWithLatestCommitmentSlot(3)
+		expectedChanges := []*api.UTXOChangesFullResponse{
+			mockUTXOChangesFull(1, 200),
+			mockUTXOChangesFull(2, 200),
+			mockUTXOChangesFull(3, 200),
+			// the commitment of slot 4 is announced by the event API
+			mockUTXOChangesFull(4, 200),
+		}
+
+		eventAPIClient := &nodeclient.EventAPIClient{
+			Client: nodeAPI,
+			MQTTClient: &topicMqttClient{payloads: map[string][][]byte{
+				nodeclient.EventAPICommitmentInfoLatest: {lo.PanicOnErr(mockAPI.JSONEncode(&api.CommitmentInfoResponse{
+					CommitmentID:   iotago.NewCommitmentID(4, iotago.Identifier{}),
+					CommitmentSlot: 4,
+				}))},
+			}},
+			Errors: make(chan error),
+		}
+		require.NoError(t, eventAPIClient.Connect(ctx))
+
+		// polling is disabled, so the latest commitment can only be received via the event API
+		feed := nodeclient.NewLedgerFeed(nodeAPI,
+			nodeclient.WithLedgerFeedEventAPIClient(eventAPIClient),
+			nodeclient.WithLedgerFeedCursorStore(cursorStore),
+			nodeclient.WithLedgerFeedPollInterval(time.Hour),
+		)
+
+		var updates []*nodeclient.LedgerUpdate
+		err := feed.Run(ctx, 1, func(_ context.Context, update *nodeclient.LedgerUpdate) error {
+			updates = append(updates, update)
+			if update.Slot == 4 {
+				cancel()
+			}
+
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+
+		require.Len(t, updates, 4)
+		for i, update := range updates {
+			require.EqualValues(t, i+1, update.Slot)
+			require.Equal(t, expectedChanges[i].CommitmentID, update.CommitmentID)
+			require.Equal(t, expectedChanges[i].CreatedOutputs[0].OutputID, update.CreatedOutputs[0].OutputID)
+		}
+
+		cursor, exists, err := cursorStore.LoadCursor()
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.EqualValues(t, 4, cursor)
+	})
+
+	t.Run("ok - resume from cursor with polling", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// slot 5 is not committed when the feed starts, the temporary error of the node is retried
+		mockInfoWithLatestCommitmentSlot(4)
+		mockGetJSON(api.CoreRouteInfo, 503, &nodeclient.HTTPErrorResponseEnvelope{})
+		mockInfoWithLatestCommitmentSlot(5)
+		mockUTXOChangesFull(5, 200)
+
+		feed := nodeclient.NewLedgerFeed(nodeAPI,
+			nodeclient.WithLedgerFeedCursorStore(cursorStore),
+			nodeclient.WithLedgerFeedPollInterval(10*time.Millisecond),
+		)
+
+		var slots []iotago.SlotIndex
+		err := feed.Run(ctx, 1, func(_ context.Context, update *nodeclient.LedgerUpdate) error {
+			slots = append(slots, update.Slot)
+			cancel()
+
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []iotago.SlotIndex{5}, slots)
+	})
+
+	t.Run("err - handler failed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		mockInfoWithLatestCommitmentSlot(6)
+		mockUTXOChangesFull(6, 200)
+
+		handlerErr := ierrors.New("handler failed")
+		feed := nodeclient.NewLedgerFeed(nodeAPI, nodeclient.WithLedgerFeedCursorStore(cursorStore))
+		err := feed.Run(ctx, 1, func(_ context.Context, _ *nodeclient.LedgerUpdate) error {
+			return handlerErr
+		})
+		require.ErrorIs(t, err, handlerErr)
+
+		// the cursor is not moved past the failed slot
+		cursor, _, err := cursorStore.LoadCursor()
+		require.NoError(t, err)
+		require.EqualValues(t, 5, cursor)
+	})
+
+	t.Run("err - slot pruned", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		mockInfoWithLatestCommitmentSlot(10)
+		mockUTXOChangesFull(1, 404)
+
+		feed := nodeclient.NewLedgerFeed(nodeAPI)
+		err := feed.Run(ctx, 1, func(_ context.Context, _ *nodeclient.LedgerUpdate) error {
+			return nil
+		})
+		require.ErrorIs(t, err, nodeclient.ErrHTTPNotFound)
+	})
+}
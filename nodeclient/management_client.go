@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	iotago "github.com/iotaledger/iota.go/v4"
 	"github.com/iotaledger/iota.go/v4/api"
 )
 
@@ -18,6 +19,14 @@ type (
 		Peers(ctx context.Context) (*api.PeersResponse, error)
 		// AddPeer adds a new peer by libp2p multi address with optional alias.
 		AddPeer(ctx context.Context, multiAddress string, alias ...string) (*api.PeerInfo, error)
+		// PruneDatabaseByEpoch prunes the database until the given epoch.
+		PruneDatabaseByEpoch(ctx context.Context, epoch iotago.EpochIndex) (*api.PruneDatabaseResponse, error)
+		// PruneDatabaseByDepth prunes the database and keeps the given amount of epochs.
+		PruneDatabaseByDepth(ctx context.Context, depth iotago.EpochIndex) (*api.PruneDatabaseResponse, error)
+		// PruneDatabaseBySize prunes the database until the given target size is reached (e.g. "30GB").
+		PruneDatabaseBySize(ctx context.Context, targetDatabaseSize string) (*api.PruneDatabaseResponse, error)
+		// CreateSnapshot creates a full snapshot of the given slot.
+		CreateSnapshot(ctx context.Context, slot iotago.SlotIndex) (*api.CreateSnapshotResponse, error)
 	}
 
 	managementClient struct {
@@ -91,3 +100,49 @@ func (client *managementClient) AddPeer(ctx context.Context, multiAddress string
 
 	return res, nil
 }
+
+// PruneDatabaseByEpoch prunes the database until the given epoch.
+func (client *managementClient) PruneDatabaseByEpoch(ctx context.Context, epoch iotago.EpochIndex) (*api.PruneDatabaseResponse, error) {
+	return client.pruneDatabase(ctx, &api.PruneDatabaseRequest{
+		Epoch: epoch,
+	})
+}
+
+// PruneDatabaseByDepth prunes the database and keeps the given amount of epochs.
+func (client *managementClient) PruneDatabaseByDepth(ctx context.Context, depth iotago.EpochIndex) (*api.PruneDatabaseResponse, error) {
+	return client.pruneDatabase(ctx, &api.PruneDatabaseRequest{
+		Depth: depth,
+	})
+}
+
+// PruneDatabaseBySize prunes the database until the given target size is reached (e.g. "30GB").
+func (client *managementClient) PruneDatabaseBySize(ctx context.Context, targetDatabaseSize string) (*api.PruneDatabaseResponse, error) {
+	return client.pruneDatabase(ctx, &api.PruneDatabaseRequest{
+		TargetDatabaseSize: targetDatabaseSize,
+	})
+}
+
+func (client *managementClient) pruneDatabase(ctx context.Context, req *api.PruneDatabaseRequest) (*api.PruneDatabaseResponse, error) {
+	res := new(api.PruneDatabaseResponse)
+	//nolint:bodyclose
+	if _, err := client.Do(ctx, http.MethodPost, api.ManagementRouteDatabasePrune, req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CreateSnapshot creates a full snapshot of the given slot.
+func (client *managementClient) CreateSnapshot(ctx context.Context, slot iotago.SlotIndex) (*api.CreateSnapshotResponse, error) {
+	req := &api.CreateSnapshotsRequest{
+		Slot: slot,
+	}
+
+	res := new(api.CreateSnapshotResponse)
+	//nolint:bodyclose
+	if _, err := client.Do(ctx, http.MethodPost, api.ManagementRouteSnapshotsCreate, req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
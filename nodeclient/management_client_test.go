@@ -167,3 +167,62 @@ func TestManagementClient_AddPeer(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, originRes, resp)
 }
+
+func TestManagementClient_PruneDatabase(t *testing.T) {
+	defer gock.Off()
+
+	originRes := &api.PruneDatabaseResponse{Epoch: 5}
+
+	originRoutes := &api.RoutesResponse{
+		Routes: []iotago.PrefixedStringUint8{api.ManagementPluginName},
+	}
+
+	mockGetJSON(api.RouteRoutes, 200, originRoutes)
+	mockPostJSON(api.ManagementRouteDatabasePrune, 200, &api.PruneDatabaseRequest{Epoch: 5}, originRes)
+	mockPostJSON(api.ManagementRouteDatabasePrune, 200, &api.PruneDatabaseRequest{Depth: 10}, originRes)
+	mockPostJSON(api.ManagementRouteDatabasePrune, 200, &api.PruneDatabaseRequest{TargetDatabaseSize: "30GB"}, originRes)
+
+	client := nodeClient(t)
+
+	management, err := client.Management(context.TODO())
+	require.NoError(t, err)
+
+	resp, err := management.PruneDatabaseByEpoch(context.Background(), 5)
+	require.NoError(t, err)
+	require.EqualValues(t, originRes, resp)
+
+	resp, err = management.PruneDatabaseByDepth(context.Background(), 10)
+	require.NoError(t, err)
+	require.EqualValues(t, originRes, resp)
+
+	resp, err = management.PruneDatabaseBySize(context.Background(), "30GB")
+	require.NoError(t, err)
+	require.EqualValues(t, originRes, resp)
+}
+
+func TestManagementClient_CreateSnapshot(t *testing.T) {
+	defer gock.Off()
+
+	originRes := &api.CreateSnapshotResponse{
+		Slot:     20,
+		FilePath: "snapshots/full_snapshot.bin",
+	}
+
+	req := &api.CreateSnapshotsRequest{Slot: 20}
+
+	originRoutes := &api.RoutesResponse{
+		Routes: []iotago.PrefixedStringUint8{api.ManagementPluginName},
+	}
+
+	mockGetJSON(api.RouteRoutes, 200, originRoutes)
+	mockPostJSON(api.ManagementRouteSnapshotsCreate, 200, req, originRes)
+
+	client := nodeClient(t)
+
+	management, err := client.Management(context.TODO())
+	require.NoError(t, err)
+
+	resp, err := management.CreateSnapshot(context.Background(), 20)
+	require.NoError(t, err)
+	require.EqualValues(t, originRes, resp)
+}
@@ -0,0 +1,491 @@
+package nodeclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+)
+
+const (
+	// DefaultPoolHealthCheckInterval is the default interval in which the nodes of a Pool are health-checked.
+	DefaultPoolHealthCheckInterval = 10 * time.Second
+	// DefaultPoolMaxCommitmentLag is the default amount of slots the latest commitment of a node may lag behind
+	// the most recent latest commitment of all nodes of a Pool for the node to be considered synced.
+	DefaultPoolMaxCommitmentLag iotago.SlotIndex = 2
+	// DefaultPoolQuorumSize is the default amount of nodes which are queried by quorum reads.
+	DefaultPoolQuorumSize = 2
+)
+
+var (
+	// ErrPoolNoHealthyNode gets returned when no healthy and synced node is available in a Pool.
+	ErrPoolNoHealthyNode = ierrors.New("no healthy node available in the pool")
+	// ErrPoolInvalidQuorumSize gets returned when the quorum size of a Pool is smaller than 1.
+	ErrPoolInvalidQuorumSize = ierrors.New("invalid quorum size")
+	// ErrPoolQuorumNotReached gets returned when not enough nodes responded to a quorum read.
+	ErrPoolQuorumNotReached = ierrors.New("quorum not reached")
+	// ErrPoolQuorumDivergence gets returned when the responses of the nodes of a quorum read diverge.
+	ErrPoolQuorumDivergence = ierrors.New("responses of the nodes diverge")
+)
+
+// QuorumDivergenceError gets returned when the responses of the nodes of a quorum read diverge.
+// It matches ErrPoolQuorumDivergence.
+type QuorumDivergenceError struct {
+	// The base URL of the node whose response the other responses were compared with.
+	ReferenceNode string
+	// The base URLs of the nodes whose responses diverge from the response of the reference node.
+	DivergingNodes []string
+}
+
+func (e *QuorumDivergenceError) Error() string {
+	return fmt.Sprintf("responses of %s diverge from the response of %s", strings.Join(e.DivergingNodes, ", "), e.ReferenceNode)
+}
+
+func (e *QuorumDivergenceError) Is(target error) bool {
+	return target == ErrPoolQuorumDivergence
+}
+
+// PoolNodeStatus is the status of a node of a Pool.
+type PoolNodeStatus struct {
+	// The base URL of the node.
+	BaseURL string
+	// Whether the node reported to be healthy and was reachable since the last health check.
+	Healthy bool
+	// Whether the node is healthy and its latest commitment doesn't lag behind the other nodes.
+	Synced bool
+	// The slot of the latest commitment of the node at the last health check.
+	LatestCommitmentSlot iotago.SlotIndex
+	// The error of the last health check or request, if any.
+	Err error
+}
+
+// WithPoolClientOptions sets the options used for the clients of the nodes and of the Pool itself.
+func WithPoolClientOptions(opts ...ClientOption) options.Option[Pool] {
+	return func(pool *Pool) {
+		pool.optsClientOptions = opts
+	}
+}
+
+// WithPoolHealthCheckInterval sets the interval in which the nodes are health-checked.
+func WithPoolHealthCheckInterval(interval time.Duration) options.Option[Pool] {
+	return func(pool *Pool) {
+		pool.optsHealthCheckInterval = interval
+	}
+}
+
+// WithPoolMaxCommitmentLag sets the amount of slots the latest commitment of a node may lag behind
+// the most recent latest commitment of all nodes for the node to be considered synced.
+func WithPoolMaxCommitmentLag(maxLag iotago.SlotIndex) options.Option[Pool] {
+	return func(pool *Pool) {
+		pool.optsMaxCommitmentLag = maxLag
+	}
+}
+
+// WithPoolQuorumSize sets the amount of nodes which are queried by quorum reads.
+// The size must be at least 1. Quorum reads fail with ErrPoolQuorumNotReached while fewer nodes are routable.
+func WithPoolQuorumSize(size int) options.Option[Pool] {
+	return func(pool *Pool) {
+		pool.optsQuorumSize = size
+	}
+}
+
+// Pool is a Client which routes its requests to the healthy and synced nodes of a set of nodes.
+// Requests are sent to the first routable node in the given order and fail over to the next node on transport errors.
+// Quorum reads compare the responses of multiple nodes and flag diverging responses.
+//
+// The BlockIssuerClient of a Pool is pinned to a single node and the EventAPIClient of a Pool connects to the first node.
+type Pool struct {
+	*Client
+
+	baseURL *url.URL
+	nodes   []*poolNode
+
+	optsClientOptions       []ClientOption
+	optsHealthCheckInterval time.Duration
+	optsMaxCommitmentLag    iotago.SlotIndex
+	optsQuorumSize          int
+}
+
+// NewPool returns a new Pool of the nodes with the given base URLs.
+// The nodes are health-checked with Client.Info and Client.Health until the given context is done.
+// Returns ErrPoolNoHealthyNode if none of the nodes is healthy and ErrPoolInvalidQuorumSize if the quorum size is invalid.
+func NewPool(ctx context.Context, baseURLs []string, opts ...options.Option[Pool]) (*Pool, error) {
+	if len(baseURLs) == 0 {
+		return nil, ierrors.Wrap(ErrPoolNoHealthyNode, "no nodes given")
+	}
+
+	pool := options.Apply(&Pool{
+		optsHealthCheckInterval: DefaultPoolHealthCheckInterval,
+		optsMaxCommitmentLag:    DefaultPoolMaxCommitmentLag,
+		optsQuorumSize:          DefaultPoolQuorumSize,
+	}, opts)
+
+	if pool.optsQuorumSize < 1 {
+		return nil, ierrors.Wrapf(ErrPoolInvalidQuorumSize, "quorum size %d must be at least 1", pool.optsQuorumSize)
+	}
+
+	for _, baseURL := range baseURLs {
+		parsedURL, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, ierrors.Wrapf(err, "invalid node URL %s", baseURL)
+		}
+
+		pool.nodes = append(pool.nodes, &poolNode{
+			baseURL: parsedURL,
+			client:  newClient(baseURL, pool.optsClientOptions...),
+		})
+	}
+	pool.baseURL = pool.nodes[0].baseURL
+
+	pool.CheckHealth(ctx)
+	if len(pool.routableNodes()) == 0 {
+		return nil, ErrPoolNoHealthyNode
+	}
+
+	// the client of the pool routes its requests through the nodes
+	pool.Client = newClient(baseURLs[0], append(pool.optsClientOptions, WithHTTPClient(&http.Client{Transport: &poolTransport{pool: pool}}))...)
+
+	ctxInfo, cancelFunc := context.WithTimeout(ctx, initInfoEndpointCallTimeout)
+	defer cancelFunc()
+	info, err := pool.Client.Info(ctxInfo)
+	if err != nil {
+		return nil, ierrors.Errorf("unable to call info endpoint for protocol parameter init: %w", err)
+	}
+	pool.Client.addProtocolParameters(info)
+
+	go pool.healthCheckLoop(ctx)
+
+	return pool, nil
+}
+
+// Nodes returns the status of the nodes of the Pool in the given order.
+func (p *Pool) Nodes() []PoolNodeStatus {
+	statuses := make([]PoolNodeStatus, len(p.nodes))
+	for i, node := range p.nodes {
+		statuses[i] = node.status()
+	}
+
+	return statuses
+}
+
+// CheckHealth health-checks all nodes of the Pool.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, node := range p.nodes {
+		wg.Add(1)
+		go func(node *poolNode) {
+			defer wg.Done()
+
+			node.checkHealth(ctx)
+		}(node)
+	}
+	wg.Wait()
+
+	var mostRecentSlot iotago.SlotIndex
+	for _, node := range p.nodes {
+		if status := node.status(); status.Healthy && status.LatestCommitmentSlot > mostRecentSlot {
+			mostRecentSlot = status.LatestCommitmentSlot
+		}
+	}
+
+	for _, node := range p.nodes {
+		node.updateSynced(mostRecentSlot, p.optsMaxCommitmentLag)
+	}
+}
+
+// QuorumOutputMetadataByID gets the metadata of an output by its ID from the quorum of nodes.
+// The latest commitment IDs of the nodes are not compared.
+func (p *Pool) QuorumOutputMetadataByID(ctx context.Context, outputID iotago.OutputID) (*api.OutputMetadata, error) {
+	return quorumRead(ctx, p, func(ctx context.Context, client *Client) (*api.OutputMetadata, error) {
+		return client.OutputMetadataByID(ctx, outputID)
+	}, func(metadata *api.OutputMetadata) any {
+		compared := *metadata
+		compared.LatestCommitmentID = iotago.EmptyCommitmentID
+
+		return &compared
+	})
+}
+
+// QuorumCommitmentByIndex gets a commitment by its slot from the quorum of nodes.
+func (p *Pool) QuorumCommitmentByIndex(ctx context.Context, slot iotago.SlotIndex) (*iotago.Commitment, error) {
+	return quorumRead(ctx, p, func(ctx context.Context, client *Client) (*iotago.Commitment, error) {
+		return client.CommitmentByIndex(ctx, slot)
+	}, nil)
+}
+
+// QuorumCommitmentByID gets a commitment by its ID from the quorum of nodes.
+func (p *Pool) QuorumCommitmentByID(ctx context.Context, commitmentID iotago.CommitmentID) (*iotago.Commitment, error) {
+	return quorumRead(ctx, p, func(ctx context.Context, client *Client) (*iotago.Commitment, error) {
+		return client.CommitmentByID(ctx, commitmentID)
+	}, nil)
+}
+
+// QuorumBlockMetadataByBlockID gets the metadata of a block by its ID from the quorum of nodes.
+func (p *Pool) QuorumBlockMetadataByBlockID(ctx context.Context, blockID iotago.BlockID) (*api.BlockMetadataResponse, error) {
+	return quorumRead(ctx, p, func(ctx context.Context, client *Client) (*api.BlockMetadataResponse, error) {
+		return client.BlockMetadataByBlockID(ctx, blockID)
+	}, nil)
+}
+
+// BlockIssuer returns the BlockIssuerClient of the first routable node which supports the block issuer plugin.
+// The returned client is pinned to that node, so the issuance info and the payloads are sent to the same node.
+func (p *Pool) BlockIssuer(ctx context.Context) (BlockIssuerClient, error) {
+	nodes := p.routableNodes()
+	if len(nodes) == 0 {
+		return nil, ErrPoolNoHealthyNode
+	}
+
+	var lastErr error
+	for _, node := range nodes {
+		blockIssuer, err := node.client.BlockIssuer(ctx)
+		if err == nil {
+			return blockIssuer, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// quorumRead reads the response from the quorum of routable nodes and checks that the responses are equal.
+// The optional comparableFunc returns the part of a response which is compared.
+func quorumRead[T any](ctx context.Context, p *Pool, readFunc func(ctx context.Context, client *Client) (T, error), comparableFunc func(response T) any) (T, error) {
+	var zero T
+
+	if p.optsQuorumSize < 1 {
+		return zero, ierrors.Wrapf(ErrPoolInvalidQuorumSize, "quorum size %d", p.optsQuorumSize)
+	}
+
+	nodes := p.routableNodes()
+	if len(nodes) < p.optsQuorumSize {
+		return zero, ierrors.Wrapf(ErrPoolQuorumNotReached, "%d of %d required nodes available", len(nodes), p.optsQuorumSize)
+	}
+	nodes = nodes[:p.optsQuorumSize]
+
+	responses := make([]T, len(nodes))
+	errs := make([]error, len(nodes))
+
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node *poolNode) {
+			defer wg.Done()
+
+			responses[i], errs[i] = readFunc(ctx, node.client)
+		}(i, node)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return zero, ierrors.Wrapf(ErrPoolQuorumNotReached, "node %s failed: %s", nodes[i].baseURL, err)
+		}
+	}
+
+	encode := func(response T) ([]byte, error) {
+		if comparableFunc != nil {
+			return p.CommittedAPI().JSONEncode(comparableFunc(response))
+		}
+
+		return p.CommittedAPI().JSONEncode(response)
+	}
+
+	reference, err := encode(responses[0])
+	if err != nil {
+		return zero, ierrors.Wrap(err, "failed to encode the response")
+	}
+
+	var divergingNodes []string
+	for i, response := range responses[1:] {
+		encoded, err := encode(response)
+		if err != nil {
+			return zero, ierrors.Wrap(err, "failed to encode the response")
+		}
+
+		if !bytes.Equal(reference, encoded) {
+			divergingNodes = append(divergingNodes, nodes[i+1].baseURL.String())
+		}
+	}
+
+	if len(divergingNodes) > 0 {
+		return zero, &QuorumDivergenceError{
+			ReferenceNode:  nodes[0].baseURL.String(),
+			DivergingNodes: divergingNodes,
+		}
+	}
+
+	return responses[0], nil
+}
+
+// routableNodes returns the healthy and synced nodes in the given order.
+func (p *Pool) routableNodes() []*poolNode {
+	nodes := make([]*poolNode, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		if node.status().Synced {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+func (p *Pool) healthCheckLoop(ctx context.Context) {
+	ticker := time.NewTicker(p.optsHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckHealth(ctx)
+		}
+	}
+}
+
+// poolTransport routes the requests of the client of a Pool to its nodes.
+type poolTransport struct {
+	pool *Pool
+}
+
+func (t *poolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// the body of every attempt is taken from GetBody
+		defer req.Body.Close()
+	}
+
+	nodes := t.pool.routableNodes()
+	if len(nodes) == 0 {
+		return nil, ErrPoolNoHealthyNode
+	}
+
+	var lastErr error
+	for _, node := range nodes {
+		nodeReq, err := t.pool.requestForNode(req, node)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := node.client.HTTPClient().Do(nodeReq)
+		if err == nil {
+			return res, nil
+		}
+
+		// canceled requests are not failed over
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+
+		node.markFailed(err)
+		lastErr = err
+
+		// the body can't be sent again
+		if req.Body != nil && req.GetBody == nil {
+			break
+		}
+	}
+
+	return nil, ierrors.Wrapf(ErrPoolNoHealthyNode, "all nodes failed, last error: %s", lastErr)
+}
+
+// requestForNode returns a copy of the given request which targets the given node.
+func (p *Pool) requestForNode(req *http.Request, node *poolNode) (*http.Request, error) {
+	target := *node.baseURL
+	target.Path = strings.TrimSuffix(node.baseURL.Path, "/") + strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(p.baseURL.Path, "/"))
+	target.RawQuery = req.URL.RawQuery
+	target.User = req.URL.User
+
+	nodeReq := req.Clone(req.Context())
+	nodeReq.URL = &target
+	nodeReq.Host = target.Host
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, ierrors.Wrap(err, "failed to get the request body")
+		}
+		nodeReq.Body = body
+	}
+
+	return nodeReq, nil
+}
+
+// poolNode is a node of a Pool.
+type poolNode struct {
+	baseURL *url.URL
+	client  *Client
+
+	mutex                sync.RWMutex
+	healthy              bool
+	synced               bool
+	latestCommitmentSlot iotago.SlotIndex
+	err                  error
+}
+
+func (n *poolNode) status() PoolNodeStatus {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	return PoolNodeStatus{
+		BaseURL:              n.baseURL.String(),
+		Healthy:              n.healthy,
+		Synced:               n.synced,
+		LatestCommitmentSlot: n.latestCommitmentSlot,
+		Err:                  n.err,
+	}
+}
+
+// checkHealth queries the info and health of the node.
+func (n *poolNode) checkHealth(ctx context.Context) {
+	info, err := n.client.Info(ctx)
+	if err != nil {
+		n.markFailed(err)
+		return
+	}
+	// the protocol parameters are needed to decode the responses of the node
+	n.client.addProtocolParameters(info)
+
+	healthy, err := n.client.Health(ctx)
+	if err != nil {
+		n.markFailed(err)
+		return
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.healthy = healthy && info.Status.IsHealthy
+	n.latestCommitmentSlot = info.Status.LatestCommitmentID.Slot()
+	n.err = nil
+}
+
+// updateSynced sets whether the node is synced given the most recent latest commitment of all nodes.
+func (n *poolNode) updateSynced(mostRecentSlot iotago.SlotIndex, maxLag iotago.SlotIndex) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.synced = n.healthy && n.latestCommitmentSlot+maxLag >= mostRecentSlot
+}
+
+// markFailed marks the node as unhealthy until the next health check.
+func (n *poolNode) markFailed(err error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.healthy = false
+	n.synced = false
+	n.err = err
+}
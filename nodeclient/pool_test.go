@@ -0,0 +1,331 @@
+package nodeclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+// poolTestNode is a minimal node which serves the info, health, routes, commitment, output metadata and block issuer info routes.
+type poolTestNode struct {
+	*httptest.Server
+
+	mutex                sync.Mutex
+	healthy              bool
+	latestCommitmentSlot iotago.SlotIndex
+	commitmentRoots      iotago.Identifier
+	outputMetadata       *api.OutputMetadata
+	blockIssuer          bool
+	requests             int
+}
+
+func newPoolTestNode(t *testing.T, latestCommitmentSlot iotago.SlotIndex) *poolTestNode {
+	t.Helper()
+
+	node := &poolTestNode{
+		healthy:              true,
+		latestCommitmentSlot: latestCommitmentSlot,
+	}
+
+	// the handlers don't run on the test goroutine, so errors are answered with an internal server error
+	writeJSON := func(w http.ResponseWriter, obj interface{}) {
+		objBytes, err := mockAPI.JSONEncode(obj)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", api.MIMEApplicationJSON)
+		_, _ = w.Write(objBytes)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.RouteHealth, func(w http.ResponseWriter, _ *http.Request) {
+		node.mutex.Lock()
+		defer node.mutex.Unlock()
+
+		if !node.healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	mux.HandleFunc(api.CoreRouteInfo, func(w http.ResponseWriter, _ *http.Request) {
+		node.mutex.Lock()
+		latestCommitmentID := iotago.NewCommitmentID(node.latestCommitmentSlot, iotago.Identifier{})
+		healthy := node.healthy
+		node.mutex.Unlock()
+
+		writeJSON(w, &api.InfoResponse{
+			Name:    "pool-test",
+			Version: "1.0.0",
+			Status: &api.InfoResNodeStatus{
+				IsHealthy:          healthy,
+				LatestCommitmentID: latestCommitmentID,
+			},
+			ProtocolParameters: []*api.InfoResProtocolParameters{
+				{StartEpoch: 0, Parameters: tpkg.IOTAMainnetV3TestProtocolParameters},
+			},
+			BaseToken: &api.InfoResBaseToken{Name: "TestCoin", TickerSymbol: "TEST", Unit: "TEST", Decimals: 6},
+			Metrics:   &api.InfoResNodeMetrics{},
+		})
+	})
+	mux.HandleFunc(api.RouteRoutes, func(w http.ResponseWriter, _ *http.Request) {
+		node.mutex.Lock()
+		routes := []iotago.PrefixedStringUint8{api.CorePluginName}
+		if node.blockIssuer {
+			routes = append(routes, api.BlockIssuerPluginName)
+		}
+		node.mutex.Unlock()
+
+		writeJSON(w, &api.RoutesResponse{Routes: routes})
+	})
+	mux.HandleFunc(api.BlockIssuerRouteInfo, func(w http.ResponseWriter, _ *http.Request) {
+		node.mutex.Lock()
+		node.requests++
+		node.mutex.Unlock()
+
+		writeJSON(w, &api.BlockIssuerInfo{BlockIssuerAddress: tpkg.RandAccountAddress().Bech32(iotago.PrefixTestnet), PowTargetTrailingZeros: 1})
+	})
+	mux.HandleFunc(strings.TrimSuffix(api.CoreRouteCommitmentBySlot, "{"+api.ParameterSlot+"}"), func(w http.ResponseWriter, r *http.Request) {
+		slot, err := strconv.ParseUint(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], 10, 32)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		node.mutex.Lock()
+		node.requests++
+		roots := node.commitmentRoots
+		node.mutex.Unlock()
+
+		writeJSON(w, iotago.NewCommitment(mockAPI.Version(), iotago.SlotIndex(slot), iotago.EmptyCommitmentID, roots, 0, 1))
+	})
+	mux.HandleFunc(strings.TrimSuffix(api.CoreRouteOutputMetadata, "{"+api.ParameterOutputID+"}/metadata"), func(w http.ResponseWriter, _ *http.Request) {
+		node.mutex.Lock()
+		node.requests++
+		metadata := node.outputMetadata
+		node.mutex.Unlock()
+
+		writeJSON(w, metadata)
+	})
+
+	node.Server = httptest.NewServer(mux)
+	t.Cleanup(node.Close)
+
+	return node
+}
+
+func (n *poolTestNode) requestCount() int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.requests
+}
+
+func TestPool_Failover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primary := newPoolTestNode(t, 10)
+	secondary := newPoolTestNode(t, 10)
+
+	pool, err := nodeclient.NewPool(ctx, []string{primary.URL, secondary.URL}, nodeclient.WithPoolHealthCheckInterval(time.Hour))
+	require.NoError(t, err)
+
+	commitment, err := pool.CommitmentByIndex(ctx, 5)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, commitment.Slot)
+	require.Equal(t, 1, primary.requestCount())
+	require.Equal(t, 0, secondary.requestCount())
+
+	// the request fails over to the secondary node if the primary node is unreachable
+	primary.Close()
+
+	commitment, err = pool.CommitmentByIndex(ctx, 6)
+	require.NoError(t, err)
+	require.EqualValues(t, 6, commitment.Slot)
+	require.Equal(t, 1, secondary.requestCount())
+
+	statuses := pool.Nodes()
+	require.False(t, statuses[0].Healthy)
+	require.Error(t, statuses[0].Err)
+	require.True(t, statuses[1].Synced)
+
+	// the unreachable node is not used anymore
+	_, err = pool.CommitmentByIndex(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, 2, secondary.requestCount())
+
+	// the request fails if no node is reachable
+	secondary.Close()
+	_, err = pool.CommitmentByIndex(ctx, 8)
+	require.ErrorIs(t, err, nodeclient.ErrPoolNoHealthyNode)
+}
+
+func TestPool_Routing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lagging := newPoolTestNode(t, 5)
+	unhealthy := newPoolTestNode(t, 10)
+	unhealthy.healthy = false
+	synced := newPoolTestNode(t, 10)
+
+	pool, err := nodeclient.NewPool(ctx, []string{lagging.URL, unhealthy.URL, synced.URL},
+		nodeclient.WithPoolHealthCheckInterval(time.Hour),
+		nodeclient.WithPoolMaxCommitmentLag(2),
+	)
+	require.NoError(t, err)
+
+	statuses := pool.Nodes()
+	require.Len(t, statuses, 3)
+	require.True(t, statuses[0].Healthy)
+	require.False(t, statuses[0].Synced)
+	require.EqualValues(t, 5, statuses[0].LatestCommitmentSlot)
+	require.False(t, statuses[1].Healthy)
+	require.True(t, statuses[2].Synced)
+
+	_, err = pool.CommitmentByIndex(ctx, 5)
+	require.NoError(t, err)
+	require.Equal(t, 0, lagging.requestCount())
+	require.Equal(t, 0, unhealthy.requestCount())
+	require.Equal(t, 1, synced.requestCount())
+
+	// the lagging node is used again once it caught up
+	lagging.mutex.Lock()
+	lagging.latestCommitmentSlot = 9
+	lagging.mutex.Unlock()
+	pool.CheckHealth(ctx)
+
+	_, err = pool.CommitmentByIndex(ctx, 5)
+	require.NoError(t, err)
+	require.Equal(t, 1, lagging.requestCount())
+}
+
+func TestPool_BlockIssuer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	withoutPlugin := newPoolTestNode(t, 10)
+	withPlugin := newPoolTestNode(t, 10)
+	withPlugin.blockIssuer = true
+
+	pool, err := nodeclient.NewPool(ctx, []string{withoutPlugin.URL, withPlugin.URL}, nodeclient.WithPoolHealthCheckInterval(time.Hour))
+	require.NoError(t, err)
+
+	blockIssuer, err := pool.BlockIssuer(ctx)
+	require.NoError(t, err)
+
+	// all requests of the block issuer are sent to the node which supports the plugin
+	for i := 0; i < 3; i++ {
+		_, err = blockIssuer.Info(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 0, withoutPlugin.requestCount())
+	require.Equal(t, 3, withPlugin.requestCount())
+
+	pool, err = nodeclient.NewPool(ctx, []string{withoutPlugin.URL}, nodeclient.WithPoolHealthCheckInterval(time.Hour))
+	require.NoError(t, err)
+
+	_, err = pool.BlockIssuer(ctx)
+	require.ErrorIs(t, err, nodeclient.ErrBlockIssuerPluginNotAvailable)
+}
+
+func TestPool_NoHealthyNode(t *testing.T) {
+	node := newPoolTestNode(t, 10)
+	node.healthy = false
+
+	_, err := nodeclient.NewPool(context.Background(), []string{node.URL})
+	require.ErrorIs(t, err, nodeclient.ErrPoolNoHealthyNode)
+
+	_, err = nodeclient.NewPool(context.Background(), nil)
+	require.ErrorIs(t, err, nodeclient.ErrPoolNoHealthyNode)
+}
+
+func TestPool_Quorum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodes := []*poolTestNode{newPoolTestNode(t, 10), newPoolTestNode(t, 10), newPoolTestNode(t, 10)}
+
+	outputID := tpkg.RandOutputID(0)
+	for i, node := range nodes {
+		node.outputMetadata = &api.OutputMetadata{
+			OutputID: outputID,
+			BlockID:  iotago.EmptyBlockID,
+			Included: &api.OutputInclusionMetadata{Slot: 3, TransactionID: outputID.TransactionID(), CommitmentID: iotago.EmptyCommitmentID},
+			// the latest commitment differs between the nodes
+			LatestCommitmentID: iotago.NewCommitmentID(iotago.SlotIndex(10+i), iotago.Identifier{}),
+		}
+	}
+
+	pool, err := nodeclient.NewPool(ctx, lo.Map(nodes, func(node *poolTestNode) string { return node.URL }),
+		nodeclient.WithPoolHealthCheckInterval(time.Hour),
+		nodeclient.WithPoolQuorumSize(3),
+	)
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		commitment, err := pool.QuorumCommitmentByIndex(ctx, 5)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, commitment.Slot)
+
+		metadata, err := pool.QuorumOutputMetadataByID(ctx, outputID)
+		require.NoError(t, err)
+		require.Equal(t, outputID, metadata.OutputID)
+
+		for _, node := range nodes {
+			require.Equal(t, 2, node.requestCount())
+		}
+	})
+
+	t.Run("err - divergence", func(t *testing.T) {
+		nodes[2].mutex.Lock()
+		nodes[2].commitmentRoots = tpkg.Rand32ByteArray()
+		nodes[2].mutex.Unlock()
+
+		_, err := pool.QuorumCommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrPoolQuorumDivergence)
+
+		var divergenceErr *nodeclient.QuorumDivergenceError
+		require.True(t, ierrors.As(err, &divergenceErr))
+		require.Equal(t, nodes[0].URL, divergenceErr.ReferenceNode)
+		require.Equal(t, []string{nodes[2].URL}, divergenceErr.DivergingNodes)
+	})
+
+	t.Run("err - quorum not reached", func(t *testing.T) {
+		nodes[1].Close()
+
+		_, err := pool.QuorumCommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrPoolQuorumNotReached)
+
+		// the closed node is not routable anymore, so fewer nodes than the quorum size are left
+		pool.CheckHealth(ctx)
+
+		_, err = pool.QuorumCommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrPoolQuorumNotReached)
+		require.ErrorContains(t, err, "2 of 3 required nodes available")
+	})
+
+	t.Run("err - invalid quorum size", func(t *testing.T) {
+		nodeURLs := []string{nodes[0].URL, nodes[2].URL}
+
+		for _, quorumSize := range []int{-1, 0} {
+			_, err := nodeclient.NewPool(ctx, nodeURLs, nodeclient.WithPoolHealthCheckInterval(time.Hour), nodeclient.WithPoolQuorumSize(quorumSize))
+			require.ErrorIs(t, err, nodeclient.ErrPoolInvalidQuorumSize)
+		}
+	})
+}
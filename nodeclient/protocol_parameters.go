@@ -0,0 +1,165 @@
+package nodeclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+)
+
+// ProtocolVersionActivatedCallback is called with the API of a protocol version once it became the committed API of the Client.
+type ProtocolVersionActivatedCallback func(api iotago.API)
+
+// WithProtocolVersionActivatedCallback sets the callback which is called when a new protocol version becomes active.
+// The callback is called after the protocol parameters of the Client were updated, so it can use the Client.
+func WithProtocolVersionActivatedCallback(callback ProtocolVersionActivatedCallback) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.protocolVersionActivatedCallback = callback
+	}
+}
+
+// WithAPIForMissingVersionCallback sets the callback which creates the API of protocol versions unknown to this library.
+// Without the callback, the protocol parameters of an unknown protocol version can't be added to the Client.
+func WithAPIForMissingVersionCallback(callback func(protocolParameters iotago.ProtocolParameters) (iotago.API, error)) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.apiForMissingVersionCallback = callback
+	}
+}
+
+// newAPIProvider returns the API provider of a Client with the given options.
+func newAPIProvider(opts *ClientOptions) *iotago.EpochBasedProvider {
+	if opts.apiForMissingVersionCallback == nil {
+		return iotago.NewEpochBasedProvider()
+	}
+
+	return iotago.NewEpochBasedProvider(iotago.WithAPIForMissingVersionCallback(opts.apiForMissingVersionCallback))
+}
+
+// addProtocolParameters adds the protocol parameters of the given info to the API provider of the Client.
+func (client *Client) addProtocolParameters(info *api.InfoResponse) {
+	for _, params := range info.ProtocolParameters {
+		client.apiProvider.AddProtocolParametersAtEpoch(params.Parameters, params.StartEpoch)
+	}
+
+	client.checkProtocolVersionActivated()
+}
+
+// setCommittedSlot updates the committed API of the Client to the protocol version of the given slot.
+func (client *Client) setCommittedSlot(slot iotago.SlotIndex) {
+	client.apiProvider.SetCommittedSlot(slot)
+
+	client.checkProtocolVersionActivated()
+}
+
+// checkProtocolVersionActivated calls the ProtocolVersionActivatedCallback if the version of the committed API increased.
+// The version the Client was initialized with doesn't trigger the callback.
+func (client *Client) checkProtocolVersionActivated() {
+	committedAPI := client.apiProvider.CommittedAPI()
+	if committedAPI == nil {
+		return
+	}
+
+	if !client.updateProtocolVersion(committedAPI.Version()) {
+		return
+	}
+
+	if client.opts.protocolVersionActivatedCallback != nil {
+		client.opts.protocolVersionActivatedCallback(committedAPI)
+	}
+}
+
+// updateProtocolVersion stores the given protocol version if it is higher than the known one
+// and returns whether it activated a new version after the one the Client was initialized with.
+func (client *Client) updateProtocolVersion(version iotago.Version) bool {
+	client.protocolVersionMutex.Lock()
+	defer client.protocolVersionMutex.Unlock()
+
+	previousVersion := client.protocolVersion
+	if version <= previousVersion {
+		return false
+	}
+	client.protocolVersion = version
+
+	return previousVersion != 0
+}
+
+// RefreshProtocolParameters gets the protocol parameters of all known protocol versions from the node,
+// adds the ones of upcoming protocol versions and updates the committed API to the latest commitment of the node.
+func (client *Client) RefreshProtocolParameters(ctx context.Context) error {
+	info, err := client.Info(ctx)
+	if err != nil {
+		return ierrors.Wrap(err, "failed to refresh the protocol parameters")
+	}
+
+	client.addProtocolParameters(info)
+
+	return nil
+}
+
+// SyncProtocolParameters refreshes the protocol parameters in the given interval until the context is done or an error occurs.
+// If an active EventAPIClient is given, the committed API also follows the commitments announced by the node,
+// and the protocol parameters are refreshed whenever a commitment of a new epoch is announced.
+// Requests which failed temporarily are repeated in the next interval.
+func (client *Client) SyncProtocolParameters(ctx context.Context, interval time.Duration, optEventAPIClient ...*EventAPIClient) error {
+	var commitmentInfoChan <-chan *api.CommitmentInfoResponse
+	if len(optEventAPIClient) > 0 && optEventAPIClient[0] != nil && optEventAPIClient[0].isActive() {
+		// only the latest commitment is of interest
+		channel, subscription := optEventAPIClient[0].LatestCommitmentInfo(WithSubscriptionBufferSize(1), WithSlowConsumerPolicy(SlowConsumerPolicyDrop))
+		if subscription.Error() == nil {
+			commitmentInfoChan = channel
+			defer func() { _ = subscription.Close() }()
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refresh := func() error {
+		if err := client.RefreshProtocolParameters(ctx); err != nil && !isTemporaryError(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	if err := refresh(); err != nil {
+		return err
+	}
+
+	var lastEpoch iotago.EpochIndex
+	var lastEpochKnown bool
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+			if err := refresh(); err != nil {
+				return err
+			}
+
+		case commitmentInfo, ok := <-commitmentInfoChan:
+			if !ok {
+				// the subscription was closed, only the interval is left
+				commitmentInfoChan = nil
+				continue
+			}
+
+			// new protocol versions are activated at the start of an epoch
+			epoch := client.CommittedAPI().TimeProvider().EpochFromSlot(commitmentInfo.CommitmentSlot)
+			if !lastEpochKnown || epoch != lastEpoch {
+				lastEpoch, lastEpochKnown = epoch, true
+
+				if err := refresh(); err != nil {
+					return err
+				}
+			}
+
+			// the info of the node might not contain the announced commitment yet
+			client.setCommittedSlot(commitmentInfo.CommitmentSlot)
+		}
+	}
+}
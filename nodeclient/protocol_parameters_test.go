@@ -0,0 +1,182 @@
+package nodeclient_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+const upgradeEpoch iotago.EpochIndex = 10
+
+// mockInfoWithUpgrade mocks the info of a node which announced protocol version 4 at upgradeEpoch.
+func mockInfoWithUpgrade(slot iotago.SlotIndex, persist ...bool) {
+	mockGetJSON(api.CoreRouteInfo, 200, &api.InfoResponse{
+		Name:    "HORNET",
+		Version: "1.0.0",
+		Status: &api.InfoResNodeStatus{
+			IsHealthy:          true,
+			LatestCommitmentID: iotago.NewCommitmentID(slot, iotago.Identifier{}),
+		},
+		ProtocolParameters: []*api.InfoResProtocolParameters{
+			{StartEpoch: 0, Parameters: tpkg.IOTAMainnetV3TestProtocolParameters},
+			{StartEpoch: upgradeEpoch, Parameters: iotago.NewV3SnapshotProtocolParameters(iotago.WithVersion(4))},
+		},
+		BaseToken: &api.InfoResBaseToken{Name: "TestCoin", TickerSymbol: "TEST", Unit: "TEST", Decimals: 6},
+		Metrics:   &api.InfoResNodeMetrics{},
+	}, persist...)
+}
+
+// upgradeTestClient returns a Client which supports protocol version 4 and sends the activated APIs to the returned channel.
+func upgradeTestClient(t *testing.T) (*nodeclient.Client, <-chan iotago.API) {
+	t.Helper()
+
+	activatedAPIs := make(chan iotago.API, 10)
+
+	mockInfoWithLatestCommitmentSlot(1)
+	client, err := nodeclient.New(nodeAPIUrl,
+		nodeclient.WithAPIForMissingVersionCallback(func(parameters iotago.ProtocolParameters) (iotago.API, error) {
+			return iotago.V3API(iotago.NewV3SnapshotProtocolParameters(iotago.WithVersion(parameters.Version()))), nil
+		}),
+		nodeclient.WithProtocolVersionActivatedCallback(func(api iotago.API) {
+			activatedAPIs <- api
+		}),
+	)
+	require.NoError(t, err)
+
+	return client, activatedAPIs
+}
+
+func TestClient_RefreshProtocolParameters(t *testing.T) {
+	defer gock.Off()
+
+	ctx := context.Background()
+	client, activatedAPIs := upgradeTestClient(t)
+	require.EqualValues(t, 3, client.CommittedAPI().Version())
+
+	// the upgrade is announced, but not active yet
+	mockInfoWithUpgrade(mockAPI.TimeProvider().EpochStart(upgradeEpoch - 1))
+	require.NoError(t, client.RefreshProtocolParameters(ctx))
+	require.EqualValues(t, 4, client.LatestAPI().Version())
+	require.EqualValues(t, 3, client.CommittedAPI().Version())
+	require.Empty(t, activatedAPIs)
+
+	mockInfoWithUpgrade(mockAPI.TimeProvider().EpochStart(upgradeEpoch))
+	require.NoError(t, client.RefreshProtocolParameters(ctx))
+	require.EqualValues(t, 4, client.CommittedAPI().Version())
+	require.Len(t, activatedAPIs, 1)
+	require.EqualValues(t, 4, (<-activatedAPIs).Version())
+
+	// the activation is only announced once
+	mockInfoWithUpgrade(mockAPI.TimeProvider().EpochStart(upgradeEpoch + 1))
+	require.NoError(t, client.RefreshProtocolParameters(ctx))
+	require.Empty(t, activatedAPIs)
+}
+
+func TestClient_ProtocolVersionActivatedCallbackUsesClient(t *testing.T) {
+	defer gock.Off()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var client *nodeclient.Client
+	callbackDone := make(chan error, 1)
+
+	mockInfoWithLatestCommitmentSlot(1)
+	client, err := nodeclient.New(nodeAPIUrl,
+		nodeclient.WithAPIForMissingVersionCallback(func(parameters iotago.ProtocolParameters) (iotago.API, error) {
+			return iotago.V3API(iotago.NewV3SnapshotProtocolParameters(iotago.WithVersion(parameters.Version()))), nil
+		}),
+		nodeclient.WithProtocolVersionActivatedCallback(func(api iotago.API) {
+			// the Client updates the committed slot with the info, which must not deadlock
+			callbackDone <- client.RefreshProtocolParameters(ctx)
+		}),
+	)
+	require.NoError(t, err)
+
+	mockInfoWithUpgrade(mockAPI.TimeProvider().EpochStart(upgradeEpoch), true)
+
+	refreshDone := make(chan error, 1)
+	go func() {
+		refreshDone <- client.RefreshProtocolParameters(ctx)
+	}()
+
+	for _, done := range []chan error{callbackDone, refreshDone} {
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-ctx.Done():
+			require.FailNow(t, "the protocol parameters were not refreshed")
+		}
+	}
+	require.EqualValues(t, 4, client.CommittedAPI().Version())
+	gock.Flush()
+}
+
+func TestClient_SyncProtocolParameters(t *testing.T) {
+	defer gock.Off()
+
+	t.Run("ok - interval", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client, activatedAPIs := upgradeTestClient(t)
+
+		// the node answers temporarily with an error in between
+		mockInfoWithUpgrade(mockAPI.TimeProvider().EpochStart(upgradeEpoch - 1))
+		mockGetJSON(api.CoreRouteInfo, 503, &nodeclient.HTTPErrorResponseEnvelope{})
+		mockInfoWithUpgrade(mockAPI.TimeProvider().EpochStart(upgradeEpoch), true)
+
+		go func() {
+			<-activatedAPIs
+			cancel()
+		}()
+
+		err := client.SyncProtocolParameters(ctx, 10*time.Millisecond)
+		require.ErrorIs(t, err, context.Canceled)
+		require.EqualValues(t, 4, client.CommittedAPI().Version())
+		gock.Flush()
+	})
+
+	t.Run("ok - commitment events", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client, activatedAPIs := upgradeTestClient(t)
+
+		// the info of the node lags behind the announced commitment
+		mockInfoWithUpgrade(mockAPI.TimeProvider().EpochStart(upgradeEpoch-1), true)
+
+		upgradeSlot := mockAPI.TimeProvider().EpochStart(upgradeEpoch)
+		eventAPIClient := &nodeclient.EventAPIClient{
+			Client: client,
+			MQTTClient: &topicMqttClient{payloads: map[string][][]byte{
+				nodeclient.EventAPICommitmentInfoLatest: {lo.PanicOnErr(mockAPI.JSONEncode(&api.CommitmentInfoResponse{
+					CommitmentID:   iotago.NewCommitmentID(upgradeSlot, iotago.Identifier{}),
+					CommitmentSlot: upgradeSlot,
+				}))},
+			}},
+			Errors: make(chan error),
+		}
+		require.NoError(t, eventAPIClient.Connect(ctx))
+
+		go func() {
+			<-activatedAPIs
+			cancel()
+		}()
+
+		// the interval is too long to activate the upgrade
+		err := client.SyncProtocolParameters(ctx, time.Hour, eventAPIClient)
+		require.ErrorIs(t, err, context.Canceled)
+		require.EqualValues(t, 4, client.CommittedAPI().Version())
+		gock.Flush()
+	})
+}
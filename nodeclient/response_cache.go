@@ -0,0 +1,196 @@
+package nodeclient
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	iotago "github.com/iotaledger/iota.go/v4"
+)
+
+// ResponseCache stores the binary encoded responses of immutable node objects, like blocks by their BlockID.
+// Implementations must be safe for concurrent use and can be backed by a persistent storage.
+type ResponseCache interface {
+	// Get returns the data stored for the given key and whether it exists.
+	Get(key string) ([]byte, bool)
+	// Set stores the data for the given key.
+	// The cache might evict the data at any time.
+	Set(key string, data []byte)
+}
+
+// ResponseCacheMetrics holds the metrics of the ResponseCache of a Client.
+type ResponseCacheMetrics struct {
+	// The amount of requests answered from the cache.
+	Hits uint64
+	// The amount of requests sent to the node because the response was not cached.
+	Misses uint64
+}
+
+// WithResponseCache sets the ResponseCache used to cache the responses of immutable node objects.
+// Only blocks by BlockID, outputs by OutputID and commitments by CommitmentID are cached,
+// the responses of all other endpoints might change.
+func WithResponseCache(cache ResponseCache) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.responseCache = cache
+	}
+}
+
+const (
+	responseCacheKeyBlock      = "block/"
+	responseCacheKeyOutput     = "output/"
+	responseCacheKeyCommitment = "commitment/"
+)
+
+// versionedResponseCacheKey returns the key of a response which is encoded with the API of the given version.
+// Blocks contain their protocol version, but outputs and commitments can only be decoded with the API they were encoded with.
+func versionedResponseCacheKey(prefix string, version iotago.Version, id string) string {
+	return fmt.Sprintf("%sv%d/%s", prefix, version, id)
+}
+
+// responseCache counts the hits and misses of a ResponseCache.
+// All methods are no-ops on a nil responseCache.
+type responseCache struct {
+	cache  ResponseCache
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newResponseCache(cache ResponseCache) *responseCache {
+	if cache == nil {
+		return nil
+	}
+
+	return &responseCache{cache: cache}
+}
+
+// get returns the data stored for the given key if it exists.
+func (c *responseCache) get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	data, exists := c.cache.Get(key)
+	if !exists {
+		c.misses.Add(1)
+
+		return nil, false
+	}
+	c.hits.Add(1)
+
+	return data, true
+}
+
+// set stores the data returned by the encode function.
+// The encode function is only called if the cache is enabled, an encoding error only prevents the caching.
+func (c *responseCache) set(key string, encode func() ([]byte, error)) {
+	if c == nil {
+		return
+	}
+
+	data, err := encode()
+	if err != nil {
+		return
+	}
+
+	c.cache.Set(key, data)
+}
+
+func (c *responseCache) metrics() ResponseCacheMetrics {
+	if c == nil {
+		return ResponseCacheMetrics{}
+	}
+
+	return ResponseCacheMetrics{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+// LRUResponseCache is an in-memory ResponseCache which evicts the least recently used entries
+// once the size of the stored keys and data exceeds its maximum size.
+type LRUResponseCache struct {
+	maxSize int
+	size    int
+	entries map[string]*list.Element
+	// the most recently used entry is at the front.
+	order *list.List
+	mutex sync.Mutex
+}
+
+type lruResponseCacheEntry struct {
+	key  string
+	data []byte
+}
+
+// NewLRUResponseCache returns a new LRUResponseCache which stores at most maxSize bytes.
+func NewLRUResponseCache(maxSize int) *LRUResponseCache {
+	return &LRUResponseCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the data stored for the given key and marks it as recently used.
+func (c *LRUResponseCache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+
+	//nolint:forcetypeassert // only entries are stored in the list
+	return element.Value.(*lruResponseCacheEntry).data, true
+}
+
+// Set stores the data for the given key and evicts the least recently used entries if the maximum size is exceeded.
+// Entries which exceed the maximum size on their own are not stored.
+func (c *LRUResponseCache) Set(key string, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		c.remove(element)
+	}
+
+	entrySize := len(key) + len(data)
+	if entrySize > c.maxSize {
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruResponseCacheEntry{key: key, data: data})
+	c.size += entrySize
+
+	for c.size > c.maxSize {
+		c.remove(c.order.Back())
+	}
+}
+
+// Size returns the size of the stored keys and data in bytes.
+func (c *LRUResponseCache) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.size
+}
+
+// Len returns the amount of stored entries.
+func (c *LRUResponseCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUResponseCache) remove(element *list.Element) {
+	//nolint:forcetypeassert // only entries are stored in the list
+	entry := c.order.Remove(element).(*lruResponseCacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= len(entry.key) + len(entry.data)
+}
+
+var _ ResponseCache = new(LRUResponseCache)
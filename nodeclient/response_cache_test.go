@@ -0,0 +1,142 @@
+package nodeclient_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+func TestLRUResponseCache(t *testing.T) {
+	cache := nodeclient.NewLRUResponseCache(10)
+
+	cache.Set("a", []byte("1234"))
+	cache.Set("b", []byte("1234"))
+	require.Equal(t, 10, cache.Size())
+	require.Equal(t, 2, cache.Len())
+
+	// "a" is used more recently than "b" afterward
+	_, exists := cache.Get("a")
+	require.True(t, exists)
+
+	cache.Set("c", []byte("12"))
+	require.Equal(t, 8, cache.Size())
+
+	_, exists = cache.Get("b")
+	require.False(t, exists)
+
+	data, exists := cache.Get("a")
+	require.True(t, exists)
+	require.Equal(t, []byte("1234"), data)
+
+	// replacing an entry updates the size
+	cache.Set("c", []byte("1"))
+	require.Equal(t, 7, cache.Size())
+	require.Equal(t, 2, cache.Len())
+
+	// entries exceeding the maximum size are not stored
+	cache.Set("d", []byte(strings.Repeat("x", 10)))
+	_, exists = cache.Get("d")
+	require.False(t, exists)
+	require.Equal(t, 2, cache.Len())
+}
+
+func TestClient_ResponseCache(t *testing.T) {
+	defer gock.Off()
+
+	nodeAPI := nodeClient(t, nodeclient.WithResponseCache(nodeclient.NewLRUResponseCache(1<<20)))
+
+	t.Run("ok - block", func(t *testing.T) {
+		blockID := tpkg.RandBlockID()
+		originBlock := testBlock()
+
+		// the mock is only used once, so the second request must be answered by the cache
+		mockGetBinary(api.EndpointWithNamedParameterValue(api.CoreRouteBlock, api.ParameterBlockID, blockID.ToHex()), 200, originBlock)
+
+		for i := 0; i < 2; i++ {
+			responseBlock, err := nodeAPI.BlockByBlockID(context.Background(), blockID)
+			require.NoError(t, err)
+			require.EqualValues(t, lo.PanicOnErr(originBlock.ID()), lo.PanicOnErr(responseBlock.ID()))
+		}
+		require.True(t, gock.IsDone())
+	})
+
+	t.Run("ok - output received as JSON", func(t *testing.T) {
+		originOutput := tpkg.RandBasicOutput(iotago.AddressEd25519)
+		originOutputProof, err := iotago.NewOutputIDProof(mockAPI, tpkg.Rand32ByteArray(), tpkg.RandSlot(), iotago.TxEssenceOutputs{originOutput}, 0)
+		require.NoError(t, err)
+		outputID, err := originOutputProof.OutputID(originOutput)
+		require.NoError(t, err)
+
+		mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteOutput, api.ParameterOutputID, outputID.ToHex()), 200, &api.OutputResponse{
+			Output:        originOutput,
+			OutputIDProof: originOutputProof,
+		})
+
+		for i := 0; i < 2; i++ {
+			responseOutput, err := nodeAPI.OutputByID(context.Background(), outputID)
+			require.NoError(t, err)
+			require.EqualValues(t, originOutput, responseOutput)
+		}
+		require.True(t, gock.IsDone())
+	})
+
+	t.Run("ok - commitment", func(t *testing.T) {
+		commitmentID := iotago.NewCommitmentID(5, tpkg.Rand32ByteArray())
+		originRes := iotago.NewCommitment(mockAPI.Version(), 5, iotago.NewCommitmentID(4, tpkg.Rand32ByteArray()), tpkg.Rand32ByteArray(), 10, 20)
+
+		mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteCommitmentByID, api.ParameterCommitmentID, commitmentID.ToHex()), 200, originRes)
+
+		for i := 0; i < 2; i++ {
+			resp, err := nodeAPI.CommitmentByID(context.Background(), commitmentID)
+			require.NoError(t, err)
+			require.EqualValues(t, originRes, resp)
+		}
+		require.True(t, gock.IsDone())
+	})
+
+	t.Run("ok - included block is not cached", func(t *testing.T) {
+		transactionID := tpkg.RandTransactionID()
+		route := api.EndpointWithNamedParameterValue(api.CoreRouteTransactionsIncludedBlock, api.ParameterTransactionID, transactionID.ToHex())
+
+		// the transaction might get included in another block
+		originBlocks := []*iotago.Block{testBlock(), testBlock()}
+		for _, originBlock := range originBlocks {
+			mockGetBinary(route, 200, originBlock)
+		}
+
+		for _, originBlock := range originBlocks {
+			responseBlock, err := nodeAPI.TransactionIncludedBlock(context.Background(), transactionID)
+			require.NoError(t, err)
+			require.EqualValues(t, lo.PanicOnErr(originBlock.ID()), lo.PanicOnErr(responseBlock.ID()))
+		}
+		require.True(t, gock.IsDone())
+	})
+
+	t.Run("ok - metadata is not cached", func(t *testing.T) {
+		blockID := tpkg.RandBlockID()
+		route := api.EndpointWithNamedParameterValue(api.CoreRouteBlockMetadata, api.ParameterBlockID, blockID.ToHex())
+
+		mockGetJSON(route, 200, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStatePending})
+		mockGetJSON(route, 200, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStateConfirmed})
+
+		meta, err := nodeAPI.BlockMetadataByBlockID(context.Background(), blockID)
+		require.NoError(t, err)
+		require.Equal(t, api.BlockStatePending, meta.BlockState)
+
+		meta, err = nodeAPI.BlockMetadataByBlockID(context.Background(), blockID)
+		require.NoError(t, err)
+		require.Equal(t, api.BlockStateConfirmed, meta.BlockState)
+		require.True(t, gock.IsDone())
+	})
+
+	require.Equal(t, nodeclient.ResponseCacheMetrics{Hits: 3, Misses: 3}, nodeAPI.ResponseCacheMetrics())
+}
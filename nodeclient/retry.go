@@ -0,0 +1,176 @@
+package nodeclient
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+)
+
+// RetryPolicy defines if and when failed requests of a Client are retried.
+//
+// Idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE) are retried on network errors and on the RetryableStatusCodes.
+// Non-idempotent requests (e.g. block submission) are only retried if the node did not process them for sure,
+// which is the case if the connection couldn't be established or the node responded with 429 too many requests,
+// unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// The maximum amount of retries after the first attempt.
+	MaxRetries int
+	// The backoff before the first retry.
+	InitialBackoff time.Duration
+	// The maximum backoff between two attempts.
+	MaxBackoff time.Duration
+	// The factor the backoff is multiplied with after every retry.
+	Multiplier float64
+	// The fraction of the backoff which is randomized, between 0 and 1.
+	Jitter float64
+	// The maximum duration requested by a "Retry-After" header which is waited for. Longer durations are not retried.
+	// Zero means no limit.
+	MaxRetryAfter time.Duration
+	// The status codes of responses which are retried.
+	RetryableStatusCodes []int
+	// Whether non-idempotent requests are retried like idempotent requests.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with sensible defaults.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxRetryAfter:  30 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy used for the requests of the Client.
+// Requests are not retried if no RetryPolicy is set.
+func WithRetryPolicy(retryPolicy *RetryPolicy) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.retryPolicy = retryPolicy
+	}
+}
+
+// shouldRetry returns whether the request with the given method which failed with the given error should be retried.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, method string, attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxRetries || ctx.Err() != nil {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if ierrors.As(err, &httpErr) {
+		if p.MaxRetryAfter > 0 && httpErr.RetryAfter > p.MaxRetryAfter {
+			return false
+		}
+
+		// the node did not process the request if it was rate limited
+		if httpErr.StatusCode == http.StatusTooManyRequests {
+			return p.isRetryableStatusCode(httpErr.StatusCode)
+		}
+
+		return p.isRetryableStatusCode(httpErr.StatusCode) && p.mayRepeat(method)
+	}
+
+	if ierrors.Is(err, ErrNetwork) {
+		// the request was not sent if the connection couldn't be established
+		var opErr *net.OpError
+		if ierrors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+
+		return p.mayRepeat(method)
+	}
+
+	return false
+}
+
+// mayRepeat returns whether requests with the given method may be sent again even if the node might have processed them.
+func (p *RetryPolicy) mayRepeat(method string) bool {
+	if p.RetryNonIdempotent {
+		return true
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *RetryPolicy) isRetryableStatusCode(statusCode int) bool {
+	for _, retryableStatusCode := range p.RetryableStatusCodes {
+		if statusCode == retryableStatusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the backoff before the retry following the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt))
+	if p.Jitter > 0 {
+		//nolint:gosec // the jitter doesn't need to be cryptographically secure
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+
+	return time.Duration(backoff)
+}
+
+// wait waits before the retry following the given attempt which failed with the given error.
+// The duration requested by a "Retry-After" header is respected if it is longer than the backoff.
+func (p *RetryPolicy) wait(ctx context.Context, attempt int, err error) error {
+	delay := p.backoff(attempt)
+
+	var httpErr *HTTPError
+	if ierrors.As(err, &httpErr) && httpErr.RetryAfter > delay {
+		delay = httpErr.RetryAfter
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ierrors.Wrapf(ctx.Err(), "retry aborted, last error: %s", err)
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses the value of a "Retry-After" header, which is either an amount of seconds or an HTTP date.
+// Returns zero if the value is empty or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,210 @@
+package nodeclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+// newRetryTestClient returns a Client for a node which serves the info route and
+// answers all other requests with the given status codes in order, followed by 200.
+func newRetryTestClient(t *testing.T, retryPolicy *nodeclient.RetryPolicy, retryAfter string, statusCodes ...int) (*nodeclient.Client, *atomic.Int32) {
+	t.Helper()
+
+	return newRetryTestClientWithErrorBody(t, retryPolicy, retryAfter, "", statusCodes...)
+}
+
+// newRetryTestClientWithErrorBody is like newRetryTestClient, but answers with the given body along with the status codes.
+func newRetryTestClientWithErrorBody(t *testing.T, retryPolicy *nodeclient.RetryPolicy, retryAfter string, errorBody string, statusCodes ...int) (*nodeclient.Client, *atomic.Int32) {
+	t.Helper()
+
+	var attempts atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.CoreRouteInfo, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", api.MIMEApplicationJSON)
+		_, _ = w.Write(lo.PanicOnErr(mockAPI.JSONEncode(&api.InfoResponse{
+			Name:    "retry-test",
+			Version: "1.0.0",
+			Status:  &api.InfoResNodeStatus{IsHealthy: true},
+			ProtocolParameters: []*api.InfoResProtocolParameters{
+				{StartEpoch: 0, Parameters: tpkg.IOTAMainnetV3TestProtocolParameters},
+			},
+			BaseToken: &api.InfoResBaseToken{Name: "TestCoin", TickerSymbol: "TEST", Unit: "TEST", Decimals: 6},
+			Metrics:   &api.InfoResNodeMetrics{},
+		})))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(attempts.Add(1))
+		if attempt <= len(statusCodes) {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCodes[attempt-1])
+			_, _ = w.Write([]byte(errorBody))
+
+			return
+		}
+
+		switch r.URL.Path {
+		case api.CoreRouteBlocks:
+			w.Header().Set("Location", tpkg.RandBlockID().ToHex())
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.Header().Set("Content-Type", api.MIMEApplicationJSON)
+			_, _ = w.Write(lo.PanicOnErr(mockAPI.JSONEncode(iotago.NewCommitment(mockAPI.Version(), 5, iotago.EmptyCommitmentID, iotago.Identifier{}, 0, 1))))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := nodeclient.New(server.URL, nodeclient.WithRetryPolicy(retryPolicy))
+	require.NoError(t, err)
+
+	return client, &attempts
+}
+
+func testRetryPolicy() *nodeclient.RetryPolicy {
+	retryPolicy := nodeclient.DefaultRetryPolicy()
+	retryPolicy.InitialBackoff = time.Millisecond
+	retryPolicy.MaxBackoff = 5 * time.Millisecond
+
+	return retryPolicy
+}
+
+func testBlock() *iotago.Block {
+	return &iotago.Block{
+		API: mockAPI,
+		Header: iotago.BlockHeader{
+			ProtocolVersion:  mockAPI.Version(),
+			NetworkID:        mockAPI.ProtocolParameters().NetworkID(),
+			SlotCommitmentID: iotago.NewEmptyCommitment(mockAPI).MustID(),
+		},
+		Signature: &iotago.Ed25519Signature{},
+		Body: &iotago.BasicBlockBody{
+			API:                mockAPI,
+			StrongParents:      tpkg.SortedRandBlockIDs(1),
+			WeakParents:        iotago.BlockIDs{},
+			ShallowLikeParents: iotago.BlockIDs{},
+		},
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ok - idempotent request is retried", func(t *testing.T) {
+		client, attempts := newRetryTestClient(t, testRetryPolicy(), "", http.StatusServiceUnavailable, http.StatusBadGateway)
+
+		commitment, err := client.CommitmentByIndex(ctx, 5)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, commitment.Slot)
+		require.EqualValues(t, 3, attempts.Load())
+	})
+
+	t.Run("ok - rate limited block submission is retried", func(t *testing.T) {
+		client, attempts := newRetryTestClient(t, testRetryPolicy(), "0", http.StatusTooManyRequests)
+
+		_, err := client.SubmitBlock(ctx, testBlock())
+		require.NoError(t, err)
+		require.EqualValues(t, 2, attempts.Load())
+	})
+
+	t.Run("ok - error response without JSON body is retried", func(t *testing.T) {
+		client, attempts := newRetryTestClientWithErrorBody(t, testRetryPolicy(), "", "<html>503 Service Unavailable</html>", http.StatusServiceUnavailable)
+
+		commitment, err := client.CommitmentByIndex(ctx, 5)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, commitment.Slot)
+		require.EqualValues(t, 2, attempts.Load())
+	})
+
+	t.Run("err - error response without JSON body", func(t *testing.T) {
+		client, attempts := newRetryTestClientWithErrorBody(t, nil, "", "<html>503 Service Unavailable</html>", http.StatusServiceUnavailable)
+
+		_, err := client.CommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPServiceUnavailable)
+		require.EqualValues(t, 1, attempts.Load())
+
+		var httpErr *nodeclient.HTTPError
+		require.True(t, ierrors.As(err, &httpErr))
+		require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+		require.Equal(t, "<html>503 Service Unavailable</html>", httpErr.Message)
+	})
+
+	t.Run("err - block submission is not repeated", func(t *testing.T) {
+		client, attempts := newRetryTestClient(t, testRetryPolicy(), "", http.StatusServiceUnavailable)
+
+		_, err := client.SubmitBlock(ctx, testBlock())
+		require.ErrorIs(t, err, nodeclient.ErrHTTPServiceUnavailable)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPServerError)
+		require.EqualValues(t, 1, attempts.Load())
+	})
+
+	t.Run("err - max retries reached", func(t *testing.T) {
+		retryPolicy := testRetryPolicy()
+		retryPolicy.MaxRetries = 2
+		client, attempts := newRetryTestClient(t, retryPolicy, "", http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+		_, err := client.CommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPServiceUnavailable)
+		require.EqualValues(t, 3, attempts.Load())
+	})
+
+	t.Run("err - retry after too long", func(t *testing.T) {
+		client, attempts := newRetryTestClient(t, testRetryPolicy(), "3600", http.StatusTooManyRequests)
+
+		_, err := client.CommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPTooManyRequests)
+		require.EqualValues(t, 1, attempts.Load())
+
+		var httpErr *nodeclient.HTTPError
+		require.True(t, ierrors.As(err, &httpErr))
+		require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+		require.Equal(t, time.Hour, httpErr.RetryAfter)
+	})
+
+	t.Run("err - client errors are not retried", func(t *testing.T) {
+		client, attempts := newRetryTestClient(t, testRetryPolicy(), "", http.StatusNotFound)
+
+		_, err := client.CommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPNotFound)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPClientError)
+		require.NotErrorIs(t, err, nodeclient.ErrHTTPServerError)
+		require.EqualValues(t, 1, attempts.Load())
+	})
+
+	t.Run("err - no retry policy", func(t *testing.T) {
+		client, attempts := newRetryTestClient(t, nil, "", http.StatusServiceUnavailable)
+
+		_, err := client.CommitmentByIndex(ctx, 5)
+		require.ErrorIs(t, err, nodeclient.ErrHTTPServiceUnavailable)
+		require.EqualValues(t, 1, attempts.Load())
+	})
+
+	t.Run("err - network error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		client, err := nodeclient.New(server.URL, nodeclient.WithRetryPolicy(testRetryPolicy()))
+		require.ErrorIs(t, err, nodeclient.ErrNetwork)
+		require.Nil(t, client)
+
+		var networkErr *nodeclient.NetworkError
+		require.True(t, ierrors.As(err, &networkErr))
+		require.NotErrorIs(t, err, nodeclient.ErrHTTPClientError)
+	})
+}
@@ -0,0 +1,589 @@
+package nodeclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+)
+
+const (
+	// DefaultTransactionTrackerPollInterval is the default interval in which the state of a tracked block is polled.
+	DefaultTransactionTrackerPollInterval = time.Second
+	// DefaultTransactionTrackerMaxReissues is the default amount of times the payload of a tracked block is reissued.
+	DefaultTransactionTrackerMaxReissues = 3
+)
+
+var (
+	// ErrBlockFailed gets returned when a tracked block was rejected or failed.
+	ErrBlockFailed = ierrors.New("block failed")
+	// ErrTransactionFailed gets returned when a tracked transaction failed.
+	ErrTransactionFailed = ierrors.New("transaction failed")
+	// ErrTrackingStateNotReached gets returned when the tracking finished before the awaited state was reached.
+	ErrTrackingStateNotReached = ierrors.New("tracking finished before the state was reached")
+)
+
+// BlockFailureError gets returned when a tracked block was rejected or failed.
+// It matches ErrBlockFailed.
+type BlockFailureError struct {
+	// The ID of the block.
+	BlockID iotago.BlockID
+	// The state of the block, either rejected or failed.
+	BlockState api.BlockState
+	// The reason reported by the node.
+	Reason api.BlockFailureReason
+}
+
+func (e *BlockFailureError) Error() string {
+	return fmt.Sprintf("block %s %s, failure reason %d", e.BlockID.ToHex(), e.BlockState, e.Reason)
+}
+
+func (e *BlockFailureError) Is(target error) bool {
+	return target == ErrBlockFailed
+}
+
+// TransactionFailureError gets returned when a tracked transaction failed.
+// It matches ErrTransactionFailed.
+type TransactionFailureError struct {
+	// The ID of the transaction.
+	TransactionID iotago.TransactionID
+	// The reason reported by the node.
+	Reason api.TransactionFailureReason
+}
+
+func (e *TransactionFailureError) Error() string {
+	return fmt.Sprintf("transaction %s failed, failure reason %d", e.TransactionID.ToHex(), e.Reason)
+}
+
+func (e *TransactionFailureError) Is(target error) bool {
+	return target == ErrTransactionFailed
+}
+
+// ReissueFunc reissues the payload of a block which was orphaned or dropped due to congestion
+// and returns the ID of the new block.
+type ReissueFunc func(ctx context.Context, failedBlock *api.BlockMetadataResponse) (iotago.BlockID, error)
+
+// WithTrackerEventAPIClient sets the connected EventAPIClient used to receive state changes.
+// The tracker keeps polling in the poll interval, so a missed event or a lost connection doesn't stall the tracking.
+func WithTrackerEventAPIClient(eventAPIClient *EventAPIClient) options.Option[TransactionTracker] {
+	return func(tracker *TransactionTracker) {
+		tracker.optsEventAPIClient = eventAPIClient
+	}
+}
+
+// WithTrackerPollInterval sets the interval in which the state of a tracked block is polled.
+func WithTrackerPollInterval(pollInterval time.Duration) options.Option[TransactionTracker] {
+	return func(tracker *TransactionTracker) {
+		tracker.optsPollInterval = pollInterval
+	}
+}
+
+// WithTrackerReissue enables the reissuing of payloads of tracked blocks which were orphaned or dropped due to congestion.
+// The payload of a tracked block is reissued at most maxReissues times.
+func WithTrackerReissue(reissueFunc ReissueFunc, maxReissues int) options.Option[TransactionTracker] {
+	return func(tracker *TransactionTracker) {
+		tracker.optsReissueFunc = reissueFunc
+		tracker.optsMaxReissues = maxReissues
+	}
+}
+
+// TransactionTracker tracks submitted blocks, and the transactions they contain, until they are finalized or failed.
+type TransactionTracker struct {
+	client *Client
+
+	optsEventAPIClient *EventAPIClient
+	optsPollInterval   time.Duration
+	optsReissueFunc    ReissueFunc
+	optsMaxReissues    int
+}
+
+// NewTransactionTracker creates a new TransactionTracker which uses the given Client.
+func NewTransactionTracker(client *Client, opts ...options.Option[TransactionTracker]) *TransactionTracker {
+	return options.Apply(&TransactionTracker{
+		client:           client,
+		optsPollInterval: DefaultTransactionTrackerPollInterval,
+		optsMaxReissues:  DefaultTransactionTrackerMaxReissues,
+	}, opts)
+}
+
+// SubmitBlock submits the given block to the node and tracks it, and the transaction it contains, until the given context is done.
+func (t *TransactionTracker) SubmitBlock(ctx context.Context, block *iotago.Block) (*TrackedBlock, error) {
+	blockID, err := t.client.SubmitBlock(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload iotago.ApplicationPayload
+	if basicBlock, isBasicBlock := block.Body.(*iotago.BasicBlockBody); isBasicBlock {
+		payload = basicBlock.Payload
+	}
+
+	transactionIDs, err := transactionIDsOfPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Track(ctx, blockID, transactionIDs...), nil
+}
+
+// SendPayload sends the given payload to the block issuer and tracks the issued block, and the transaction it contains, until the given context is done.
+func (t *TransactionTracker) SendPayload(ctx context.Context, blockIssuer BlockIssuerClient, payload iotago.ApplicationPayload, commitmentID iotago.CommitmentID, numPoWWorkers ...int) (*TrackedBlock, error) {
+	transactionIDs, err := transactionIDsOfPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	blockCreatedResponse, err := blockIssuer.SendPayload(ctx, payload, commitmentID, numPoWWorkers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Track(ctx, blockCreatedResponse.BlockID, transactionIDs...), nil
+}
+
+// Track tracks the block with the given ID, and optionally the transaction it contains, until the given context is done.
+// If no transaction ID is given, the transaction is tracked as soon as the node reports it in the block metadata.
+// The tracking stops when the transaction, or the block if it doesn't contain a transaction, is finalized or failed.
+func (t *TransactionTracker) Track(ctx context.Context, blockID iotago.BlockID, optTransactionID ...iotago.TransactionID) *TrackedBlock {
+	trackedBlock := newTrackedBlock(blockID, optTransactionID...)
+
+	go t.track(ctx, trackedBlock)
+
+	return trackedBlock
+}
+
+func (t *TransactionTracker) track(ctx context.Context, trackedBlock *TrackedBlock) {
+	pollTicker := time.NewTicker(t.optsPollInterval)
+	defer pollTicker.Stop()
+
+	var subscriptions []*EventAPIClientSubscription
+	defer func() { closeSubscriptions(subscriptions) }()
+
+	for !trackedBlock.isDone() {
+		// (re)subscribe to the events of the currently tracked block
+		closeSubscriptions(subscriptions)
+
+		var blockMetadataChan <-chan *api.BlockMetadataResponse
+		var includedBlockChan <-chan *iotago.Block
+		blockMetadataChan, includedBlockChan, subscriptions = t.subscribe(trackedBlock)
+
+		// the state might have changed before the subscription was made
+		blockChanged := t.poll(ctx, trackedBlock)
+
+		for !blockChanged && !trackedBlock.isDone() {
+			select {
+			case <-ctx.Done():
+				trackedBlock.finish(ierrors.Wrap(ctx.Err(), "tracking stopped"))
+				return
+			case <-pollTicker.C:
+				// events might get lost, so the state is polled in addition to them
+				blockChanged = t.poll(ctx, trackedBlock)
+			case metadata, ok := <-blockMetadataChan:
+				if !ok {
+					// the subscription was closed, only rely on polling
+					blockMetadataChan, includedBlockChan = nil, nil
+					continue
+				}
+				blockChanged = t.processBlockMetadata(ctx, trackedBlock, metadata)
+			case block, ok := <-includedBlockChan:
+				if !ok {
+					includedBlockChan = nil
+					continue
+				}
+				blockChanged = t.processIncludedBlock(trackedBlock, block)
+			}
+		}
+	}
+}
+
+// subscribe subscribes to the state changes of the tracked block and to the block including the tracked transaction.
+// It returns nil channels if the EventAPIClient is not available.
+func (t *TransactionTracker) subscribe(trackedBlock *TrackedBlock) (<-chan *api.BlockMetadataResponse, <-chan *iotago.Block, []*EventAPIClientSubscription) {
+	if t.optsEventAPIClient == nil || !t.optsEventAPIClient.isActive() {
+		return nil, nil, nil
+	}
+
+	blockMetadataChan, blockMetadataSubscription := t.optsEventAPIClient.BlockMetadataChange(trackedBlock.BlockID())
+	if blockMetadataSubscription.Error() != nil {
+		return nil, nil, nil
+	}
+	subscriptions := []*EventAPIClientSubscription{blockMetadataSubscription}
+
+	transactionID, hasTransaction := trackedBlock.TransactionID()
+	if !hasTransaction {
+		return blockMetadataChan, nil, subscriptions
+	}
+
+	// the included block is only needed to follow the transaction if it gets included in another block
+	includedBlockChan, includedBlockSubscription := t.optsEventAPIClient.TransactionIncludedBlock(transactionID)
+	if includedBlockSubscription.Error() != nil {
+		return blockMetadataChan, nil, subscriptions
+	}
+
+	return blockMetadataChan, includedBlockChan, append(subscriptions, includedBlockSubscription)
+}
+
+// poll queries the state of the tracked block and transaction and returns whether the tracked block changed.
+func (t *TransactionTracker) poll(ctx context.Context, trackedBlock *TrackedBlock) bool {
+	// errors are ignored, the block might not be known to the node yet and the next poll retries
+	metadata, err := t.client.BlockMetadataByBlockID(ctx, trackedBlock.BlockID())
+	if err != nil {
+		return false
+	}
+
+	// the transaction might have been included in another block
+	if transactionID, hasTransaction := trackedBlock.TransactionID(); hasTransaction {
+		if transactionMetadata, err := t.client.TransactionMetadata(ctx, transactionID); err == nil {
+			metadata.TransactionMetadata = transactionMetadata
+		}
+	}
+
+	return t.processBlockMetadata(ctx, trackedBlock, metadata)
+}
+
+// processBlockMetadata updates the tracked block with the given metadata and returns whether the tracked block changed.
+func (t *TransactionTracker) processBlockMetadata(ctx context.Context, trackedBlock *TrackedBlock, metadata *api.BlockMetadataResponse) bool {
+	if metadata == nil || metadata.BlockID != trackedBlock.BlockID() {
+		return false
+	}
+
+	trackedBlock.update(metadata)
+
+	_, hasTransaction := trackedBlock.TransactionID()
+	transactionState := trackedBlock.TransactionState()
+
+	switch {
+	case metadata.TransactionMetadata != nil && metadata.TransactionMetadata.TransactionState == api.TransactionStateFailed:
+		trackedBlock.finish(&TransactionFailureError{
+			TransactionID: metadata.TransactionMetadata.TransactionID,
+			Reason:        metadata.TransactionMetadata.TransactionFailureReason,
+		})
+
+	case hasTransaction && transactionState == api.TransactionStateFinalized,
+		!hasTransaction && metadata.BlockState == api.BlockStateFinalized:
+		trackedBlock.finish(nil)
+
+	case metadata.BlockState == api.BlockStateRejected || metadata.BlockState == api.BlockStateFailed:
+		// the transaction was already accepted in another block
+		if hasTransaction && transactionState >= api.TransactionStateAccepted {
+			return false
+		}
+
+		reissued, err := t.reissue(ctx, trackedBlock, metadata)
+		if reissued {
+			return true
+		}
+
+		failureErr := &BlockFailureError{
+			BlockID:    metadata.BlockID,
+			BlockState: metadata.BlockState,
+			Reason:     metadata.BlockFailureReason,
+		}
+		if err != nil {
+			trackedBlock.finish(ierrors.Join(failureErr, ierrors.Wrap(err, "failed to reissue the payload")))
+
+			return false
+		}
+
+		trackedBlock.finish(failureErr)
+	}
+
+	return false
+}
+
+// processIncludedBlock follows the tracked transaction into the block which includes it and returns whether the tracked block changed.
+func (t *TransactionTracker) processIncludedBlock(trackedBlock *TrackedBlock, block *iotago.Block) bool {
+	blockID, err := block.ID()
+	if err != nil {
+		return false
+	}
+
+	return trackedBlock.switchBlock(blockID)
+}
+
+// reissue reissues the payload of the given failed block if possible and returns whether it was reissued.
+func (t *TransactionTracker) reissue(ctx context.Context, trackedBlock *TrackedBlock, metadata *api.BlockMetadataResponse) (bool, error) {
+	if t.optsReissueFunc == nil || !isReissuable(metadata) || trackedBlock.Reissues() >= t.optsMaxReissues {
+		return false, nil
+	}
+
+	blockID, err := t.optsReissueFunc(ctx, metadata)
+	if err != nil {
+		return false, err
+	}
+
+	trackedBlock.reissued(blockID)
+
+	return true, nil
+}
+
+// isReissuable returns whether the payload of the given failed block can succeed in a new block.
+func isReissuable(metadata *api.BlockMetadataResponse) bool {
+	switch metadata.BlockFailureReason {
+	case api.BlockFailureDroppedDueToCongestion, api.BlockFailureOrphanedDueNegativeCreditsBalance:
+		return true
+	case api.BlockFailureNone:
+		// the block was orphaned
+		return metadata.BlockState == api.BlockStateRejected
+	default:
+		return false
+	}
+}
+
+func closeSubscriptions(subscriptions []*EventAPIClientSubscription) {
+	for _, subscription := range subscriptions {
+		_ = subscription.Close()
+	}
+}
+
+// transactionIDsOfPayload returns the ID of the transaction of the given payload, if it is a transaction.
+func transactionIDsOfPayload(payload iotago.ApplicationPayload) ([]iotago.TransactionID, error) {
+	signedTransaction, isTransaction := payload.(*iotago.SignedTransaction)
+	if !isTransaction {
+		return nil, nil
+	}
+
+	transactionID, err := signedTransaction.Transaction.ID()
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to calculate the transaction ID")
+	}
+
+	return []iotago.TransactionID{transactionID}, nil
+}
+
+// TrackedBlock is a handle to await the states of a block, and of the transaction it contains, tracked by a TransactionTracker.
+// The tracked block changes if its payload is reissued or the transaction is included in another block.
+type TrackedBlock struct {
+	mutex sync.RWMutex
+
+	blockID          iotago.BlockID
+	transactionID    *iotago.TransactionID
+	blockState       api.BlockState
+	transactionState api.TransactionState
+	reissues         int
+
+	done bool
+	err  error
+	// gets closed and replaced every time the tracked block changes.
+	changed chan struct{}
+}
+
+func newTrackedBlock(blockID iotago.BlockID, optTransactionID ...iotago.TransactionID) *TrackedBlock {
+	trackedBlock := &TrackedBlock{
+		blockID:    blockID,
+		blockState: api.BlockStatePending,
+		changed:    make(chan struct{}),
+	}
+
+	if len(optTransactionID) > 0 {
+		trackedBlock.transactionID = &optTransactionID[0]
+		trackedBlock.transactionState = api.TransactionStatePending
+	}
+
+	return trackedBlock
+}
+
+// BlockID returns the ID of the currently tracked block.
+func (b *TrackedBlock) BlockID() iotago.BlockID {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.blockID
+}
+
+// TransactionID returns the ID of the tracked transaction and whether a transaction is tracked.
+func (b *TrackedBlock) TransactionID() (iotago.TransactionID, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.transactionID == nil {
+		return iotago.EmptyTransactionID, false
+	}
+
+	return *b.transactionID, true
+}
+
+// BlockState returns the last known state of the currently tracked block.
+func (b *TrackedBlock) BlockState() api.BlockState {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.blockState
+}
+
+// TransactionState returns the last known state of the tracked transaction.
+func (b *TrackedBlock) TransactionState() api.TransactionState {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.transactionState
+}
+
+// Reissues returns how many times the payload of the block was reissued.
+func (b *TrackedBlock) Reissues() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.reissues
+}
+
+// Err returns the error the tracking finished with.
+// It is a BlockFailureError or TransactionFailureError if the block or transaction failed.
+func (b *TrackedBlock) Err() error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.err
+}
+
+// AwaitBlockState waits until the tracked block reached the given state.
+// The accepted, confirmed and finalized states are also reached by any later state.
+func (b *TrackedBlock) AwaitBlockState(ctx context.Context, state api.BlockState) error {
+	return b.await(ctx, func() bool {
+		if state > api.BlockStateFinalized {
+			return b.blockState == state
+		}
+
+		return b.blockState >= state && b.blockState <= api.BlockStateFinalized
+	})
+}
+
+// AwaitTransactionState waits until the tracked transaction reached the given state.
+// The accepted, confirmed and finalized states are also reached by any later state.
+func (b *TrackedBlock) AwaitTransactionState(ctx context.Context, state api.TransactionState) error {
+	return b.await(ctx, func() bool {
+		if b.transactionID == nil {
+			return false
+		}
+
+		if state > api.TransactionStateFinalized {
+			return b.transactionState == state
+		}
+
+		return b.transactionState >= state && b.transactionState <= api.TransactionStateFinalized
+	})
+}
+
+// AwaitAccepted waits until the tracked transaction, or the block if it doesn't contain a transaction, is accepted.
+func (b *TrackedBlock) AwaitAccepted(ctx context.Context) error {
+	return b.awaitState(ctx, api.BlockStateAccepted, api.TransactionStateAccepted)
+}
+
+// AwaitConfirmed waits until the tracked transaction, or the block if it doesn't contain a transaction, is confirmed.
+func (b *TrackedBlock) AwaitConfirmed(ctx context.Context) error {
+	return b.awaitState(ctx, api.BlockStateConfirmed, api.TransactionStateConfirmed)
+}
+
+// AwaitFinalized waits until the tracked transaction, or the block if it doesn't contain a transaction, is finalized.
+func (b *TrackedBlock) AwaitFinalized(ctx context.Context) error {
+	return b.awaitState(ctx, api.BlockStateFinalized, api.TransactionStateFinalized)
+}
+
+func (b *TrackedBlock) awaitState(ctx context.Context, blockState api.BlockState, transactionState api.TransactionState) error {
+	if _, hasTransaction := b.TransactionID(); hasTransaction {
+		return b.AwaitTransactionState(ctx, transactionState)
+	}
+
+	return b.AwaitBlockState(ctx, blockState)
+}
+
+// await waits until the given condition is met, the tracking finished or the context is done.
+// The condition is evaluated while holding the lock.
+func (b *TrackedBlock) await(ctx context.Context, reached func() bool) error {
+	for {
+		b.mutex.RLock()
+		isReached, done, err, changed := reached(), b.done, b.err, b.changed
+		b.mutex.RUnlock()
+
+		switch {
+		case isReached:
+			return nil
+		case err != nil:
+			return err
+		case done:
+			return ErrTrackingStateNotReached
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (b *TrackedBlock) isDone() bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.done
+}
+
+// update sets the states of the given metadata and starts tracking the transaction contained in the block.
+func (b *TrackedBlock) update(metadata *api.BlockMetadataResponse) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.blockState = metadata.BlockState
+
+	if transactionMetadata := metadata.TransactionMetadata; transactionMetadata != nil {
+		if b.transactionID == nil {
+			transactionID := transactionMetadata.TransactionID
+			b.transactionID = &transactionID
+		}
+
+		if transactionMetadata.TransactionID == *b.transactionID {
+			b.transactionState = transactionMetadata.TransactionState
+		}
+	}
+
+	b.notifyChanged()
+}
+
+// switchBlock tracks the given block which includes the tracked transaction and returns whether the block changed.
+func (b *TrackedBlock) switchBlock(blockID iotago.BlockID) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.blockID == blockID {
+		return false
+	}
+
+	b.blockID = blockID
+	b.blockState = api.BlockStatePending
+	b.notifyChanged()
+
+	return true
+}
+
+// reissued tracks the given block which contains the reissued payload.
+func (b *TrackedBlock) reissued(blockID iotago.BlockID) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.blockID = blockID
+	b.blockState = api.BlockStatePending
+	b.reissues++
+	b.notifyChanged()
+}
+
+// finish stops the tracking with the given error.
+func (b *TrackedBlock) finish(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.done = true
+	b.err = err
+	b.notifyChanged()
+}
+
+// notifyChanged wakes up all waiting calls, the lock must be held by the caller.
+func (b *TrackedBlock) notifyChanged() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
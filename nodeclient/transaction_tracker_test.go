@@ -0,0 +1,263 @@
+//nolint:forcetypeassert
+package nodeclient_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+)
+
+// topicMqttClient is a mockMqttClient which delivers the payloads of the subscribed topic.
+type topicMqttClient struct {
+	mockMqttClient
+	payloads map[string][][]byte
+}
+
+func (m *topicMqttClient) Subscribe(topic string, _ byte, callback mqtt.MessageHandler) mqtt.Token {
+	go func() {
+		for _, payload := range m.payloads[topic] {
+			callback(m, &mockMsg{payload: payload})
+		}
+	}()
+
+	return &mockToken{}
+}
+
+func mockBlockMetadata(blockID iotago.BlockID, metadata *api.BlockMetadataResponse, persist ...bool) {
+	mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteBlockMetadata, api.ParameterBlockID, blockID.ToHex()), 200, metadata, persist...)
+}
+
+func mockTransactionMetadata(transactionID iotago.TransactionID, metadata *api.TransactionMetadataResponse, persist ...bool) {
+	mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteTransactionsMetadata, api.ParameterTransactionID, transactionID.ToHex()), 200, metadata, persist...)
+}
+
+func TestTransactionTracker_Polling(t *testing.T) {
+	defer gock.Off()
+
+	nodeAPI := nodeClient(t)
+	tracker := nodeclient.NewTransactionTracker(nodeAPI, nodeclient.WithTrackerPollInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("ok - block", func(t *testing.T) {
+		blockID := tpkg.RandBlockID()
+		mockBlockMetadata(blockID, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStatePending})
+		mockBlockMetadata(blockID, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStateAccepted})
+		mockBlockMetadata(blockID, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStateFinalized}, true)
+
+		trackedBlock := tracker.Track(ctx, blockID)
+		require.NoError(t, trackedBlock.AwaitAccepted(ctx))
+		require.NoError(t, trackedBlock.AwaitFinalized(ctx))
+		require.NoError(t, trackedBlock.Err())
+		require.Equal(t, api.BlockStateFinalized, trackedBlock.BlockState())
+
+		// the tracking finished, so the rejected state is never reached
+		require.ErrorIs(t, trackedBlock.AwaitBlockState(ctx, api.BlockStateRejected), nodeclient.ErrTrackingStateNotReached)
+	})
+
+	t.Run("ok - transaction", func(t *testing.T) {
+		blockID := tpkg.RandBlockID()
+		transactionID := tpkg.RandTransactionID()
+		mockBlockMetadata(blockID, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStateConfirmed}, true)
+		mockTransactionMetadata(transactionID, &api.TransactionMetadataResponse{TransactionID: transactionID, TransactionState: api.TransactionStateAccepted})
+		mockTransactionMetadata(transactionID, &api.TransactionMetadataResponse{TransactionID: transactionID, TransactionState: api.TransactionStateFinalized}, true)
+
+		trackedBlock := tracker.Track(ctx, blockID, transactionID)
+		require.NoError(t, trackedBlock.AwaitFinalized(ctx))
+		require.Equal(t, api.TransactionStateFinalized, trackedBlock.TransactionState())
+		require.Equal(t, api.BlockStateConfirmed, trackedBlock.BlockState())
+	})
+
+	t.Run("err - transaction failed", func(t *testing.T) {
+		blockID := tpkg.RandBlockID()
+		transactionID := tpkg.RandTransactionID()
+		mockBlockMetadata(blockID, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStateAccepted}, true)
+		mockTransactionMetadata(transactionID, &api.TransactionMetadataResponse{
+			TransactionID:            transactionID,
+			TransactionState:         api.TransactionStateFailed,
+			TransactionFailureReason: api.TxFailureUTXOInputAlreadySpent,
+		}, true)
+
+		trackedBlock := tracker.Track(ctx, blockID, transactionID)
+		err := trackedBlock.AwaitConfirmed(ctx)
+		require.ErrorIs(t, err, nodeclient.ErrTransactionFailed)
+
+		var failureErr *nodeclient.TransactionFailureError
+		require.True(t, ierrors.As(err, &failureErr))
+		require.Equal(t, transactionID, failureErr.TransactionID)
+		require.Equal(t, api.TxFailureUTXOInputAlreadySpent, failureErr.Reason)
+	})
+
+	t.Run("err - block failed", func(t *testing.T) {
+		blockID := tpkg.RandBlockID()
+		mockBlockMetadata(blockID, &api.BlockMetadataResponse{
+			BlockID:            blockID,
+			BlockState:         api.BlockStateFailed,
+			BlockFailureReason: api.BlockFailureSignatureInvalid,
+		}, true)
+
+		trackedBlock := tracker.Track(ctx, blockID)
+		err := trackedBlock.AwaitAccepted(ctx)
+		require.ErrorIs(t, err, nodeclient.ErrBlockFailed)
+
+		var failureErr *nodeclient.BlockFailureError
+		require.True(t, ierrors.As(err, &failureErr))
+		require.Equal(t, api.BlockFailureSignatureInvalid, failureErr.Reason)
+		require.NoError(t, trackedBlock.AwaitBlockState(ctx, api.BlockStateFailed))
+	})
+
+	t.Run("err - context done", func(t *testing.T) {
+		trackingCtx, trackingCancel := context.WithCancel(ctx)
+		trackedBlock := tracker.Track(trackingCtx, tpkg.RandBlockID())
+		trackingCancel()
+
+		require.ErrorIs(t, trackedBlock.AwaitFinalized(ctx), context.Canceled)
+	})
+}
+
+func TestTransactionTracker_Reissue(t *testing.T) {
+	defer gock.Off()
+
+	nodeAPI := nodeClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	droppedBlockID := tpkg.RandBlockID()
+	orphanedBlockID := tpkg.RandBlockID()
+	reissuedBlockID := tpkg.RandBlockID()
+	mockBlockMetadata(droppedBlockID, &api.BlockMetadataResponse{
+		BlockID:            droppedBlockID,
+		BlockState:         api.BlockStateFailed,
+		BlockFailureReason: api.BlockFailureDroppedDueToCongestion,
+	}, true)
+	mockBlockMetadata(orphanedBlockID, &api.BlockMetadataResponse{BlockID: orphanedBlockID, BlockState: api.BlockStateRejected}, true)
+	mockBlockMetadata(reissuedBlockID, &api.BlockMetadataResponse{BlockID: reissuedBlockID, BlockState: api.BlockStateFinalized}, true)
+
+	reissuedBlockIDs := []iotago.BlockID{orphanedBlockID, reissuedBlockID}
+	var failedBlockIDs []iotago.BlockID
+	reissue := func(_ context.Context, failedBlock *api.BlockMetadataResponse) (iotago.BlockID, error) {
+		failedBlockIDs = append(failedBlockIDs, failedBlock.BlockID)
+		blockID := reissuedBlockIDs[0]
+		reissuedBlockIDs = reissuedBlockIDs[1:]
+
+		return blockID, nil
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		tracker := nodeclient.NewTransactionTracker(nodeAPI, nodeclient.WithTrackerPollInterval(10*time.Millisecond), nodeclient.WithTrackerReissue(reissue, 2))
+
+		trackedBlock := tracker.Track(ctx, droppedBlockID)
+		require.NoError(t, trackedBlock.AwaitFinalized(ctx))
+		require.Equal(t, 2, trackedBlock.Reissues())
+		require.Equal(t, reissuedBlockID, trackedBlock.BlockID())
+		require.Equal(t, []iotago.BlockID{droppedBlockID, orphanedBlockID}, failedBlockIDs)
+	})
+
+	t.Run("err - max reissues reached", func(t *testing.T) {
+		tracker := nodeclient.NewTransactionTracker(nodeAPI, nodeclient.WithTrackerPollInterval(10*time.Millisecond), nodeclient.WithTrackerReissue(reissue, 0))
+
+		err := tracker.Track(ctx, droppedBlockID).AwaitFinalized(ctx)
+		require.ErrorIs(t, err, nodeclient.ErrBlockFailed)
+
+		var failureErr *nodeclient.BlockFailureError
+		require.True(t, ierrors.As(err, &failureErr))
+		require.Equal(t, api.BlockFailureDroppedDueToCongestion, failureErr.Reason)
+	})
+
+	t.Run("err - reissue failed", func(t *testing.T) {
+		reissueErr := ierrors.New("no mana")
+		tracker := nodeclient.NewTransactionTracker(nodeAPI, nodeclient.WithTrackerPollInterval(10*time.Millisecond), nodeclient.WithTrackerReissue(func(context.Context, *api.BlockMetadataResponse) (iotago.BlockID, error) {
+			return iotago.EmptyBlockID, reissueErr
+		}, 1))
+
+		err := tracker.Track(ctx, droppedBlockID).AwaitFinalized(ctx)
+		require.ErrorIs(t, err, nodeclient.ErrBlockFailed)
+		require.ErrorIs(t, err, reissueErr)
+	})
+}
+
+func TestTransactionTracker_EventAPI(t *testing.T) {
+	defer gock.Off()
+
+	nodeAPI := nodeClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	blockID := tpkg.RandBlockID()
+	transactionID := tpkg.RandTransactionID()
+	metadataTopic := "block-metadata/" + blockID.ToHex()
+
+	// the node doesn't know the block yet when it is polled after subscribing
+	mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteBlockMetadata, api.ParameterBlockID, blockID.ToHex()), 404, &nodeclient.HTTPErrorResponseEnvelope{}, true)
+
+	eventAPIClient := &nodeclient.EventAPIClient{
+		Client: nodeAPI,
+		MQTTClient: &topicMqttClient{payloads: map[string][][]byte{
+			metadataTopic: {
+				lo.PanicOnErr(mockAPI.JSONEncode(&api.BlockMetadataResponse{
+					BlockID:             blockID,
+					BlockState:          api.BlockStateAccepted,
+					TransactionMetadata: &api.TransactionMetadataResponse{TransactionID: transactionID, TransactionState: api.TransactionStateAccepted},
+				})),
+				lo.PanicOnErr(mockAPI.JSONEncode(&api.BlockMetadataResponse{
+					BlockID:             blockID,
+					BlockState:          api.BlockStateFinalized,
+					TransactionMetadata: &api.TransactionMetadataResponse{TransactionID: transactionID, TransactionState: api.TransactionStateFinalized},
+				})),
+			},
+		}},
+		Errors: make(chan error),
+	}
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	// polling is effectively disabled, so the states can only be received via the event API
+	tracker := nodeclient.NewTransactionTracker(nodeAPI, nodeclient.WithTrackerEventAPIClient(eventAPIClient), nodeclient.WithTrackerPollInterval(time.Hour))
+
+	trackedBlock := tracker.Track(ctx, blockID)
+	require.NoError(t, trackedBlock.AwaitFinalized(ctx))
+
+	// the transaction is tracked as soon as it is reported in the block metadata
+	trackedTransactionID, hasTransaction := trackedBlock.TransactionID()
+	require.True(t, hasTransaction)
+	require.Equal(t, transactionID, trackedTransactionID)
+	require.Equal(t, api.TransactionStateFinalized, trackedBlock.TransactionState())
+}
+
+func TestTransactionTracker_EventAPIMissedEvents(t *testing.T) {
+	defer gock.Off()
+
+	nodeAPI := nodeClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	blockID := tpkg.RandBlockID()
+	mockGetJSON(api.EndpointWithNamedParameterValue(api.CoreRouteBlockMetadata, api.ParameterBlockID, blockID.ToHex()), 404, &nodeclient.HTTPErrorResponseEnvelope{})
+	mockBlockMetadata(blockID, &api.BlockMetadataResponse{BlockID: blockID, BlockState: api.BlockStateFinalized}, true)
+
+	// the event API never delivers the state changes of the block
+	eventAPIClient := &nodeclient.EventAPIClient{
+		Client:     nodeAPI,
+		MQTTClient: &topicMqttClient{payloads: map[string][][]byte{}},
+		Errors:     make(chan error),
+	}
+	require.NoError(t, eventAPIClient.Connect(ctx))
+
+	tracker := nodeclient.NewTransactionTracker(nodeAPI, nodeclient.WithTrackerEventAPIClient(eventAPIClient), nodeclient.WithTrackerPollInterval(10*time.Millisecond))
+
+	require.NoError(t, tracker.Track(ctx, blockID).AwaitFinalized(ctx))
+}
@@ -0,0 +1,138 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/iotaledger/iota-crypto-demo/pkg/bip32path"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+)
+
+const (
+	// DefaultAddressGapLimit is the default amount of consecutive unused addresses after which the discovery of a chain stops.
+	DefaultAddressGapLimit = 20
+	// DefaultAccountGapLimit is the default amount of consecutive unused accounts after which the discovery stops.
+	DefaultAccountGapLimit = 1
+)
+
+// DiscoveryOptions defines the options for the discovery of used BIP44 paths.
+type DiscoveryOptions struct {
+	addressGapLimit uint32
+	accountGapLimit uint32
+	changeIndices   []uint32
+}
+
+// WithDiscoveryAddressGapLimit sets the amount of consecutive unused address indices after which
+// the discovery of the addresses of an account and change index stops.
+func WithDiscoveryAddressGapLimit(gapLimit uint32) options.Option[DiscoveryOptions] {
+	return func(o *DiscoveryOptions) {
+		o.addressGapLimit = gapLimit
+	}
+}
+
+// WithDiscoveryAccountGapLimit sets the amount of consecutive unused account indices after which the discovery stops.
+func WithDiscoveryAccountGapLimit(gapLimit uint32) options.Option[DiscoveryOptions] {
+	return func(o *DiscoveryOptions) {
+		o.accountGapLimit = gapLimit
+	}
+}
+
+// WithDiscoveryChangeIndices sets the change indices which are scanned for every account.
+// Defaults to the external (0) and internal (1) chain.
+func WithDiscoveryChangeIndices(changeIndices ...uint32) options.Option[DiscoveryOptions] {
+	return func(o *DiscoveryOptions) {
+		o.changeIndices = changeIndices
+	}
+}
+
+// DiscoverPaths scans the BIP44 account, change and address indices of the coin type of the given KeyManager
+// and returns the paths whose Ed25519 or implicit account creation address holds unspent outputs.
+// The scan of an account and change index stops after the address gap limit of consecutive unused addresses,
+// the scan of the accounts stops after the account gap limit of consecutive unused accounts.
+//
+// The indexer only knows about unspent outputs, so addresses whose outputs are all spent are treated as unused.
+func DiscoverPaths(ctx context.Context, keyManager *KeyManager, indexer nodeclient.IndexerClient, netPrefix iotago.NetworkPrefix, opts ...options.Option[DiscoveryOptions]) ([]bip32path.Path, error) {
+	discoveryOpts := options.Apply(&DiscoveryOptions{
+		addressGapLimit: DefaultAddressGapLimit,
+		accountGapLimit: DefaultAccountGapLimit,
+		changeIndices:   []uint32{0, 1},
+	}, opts)
+
+	if discoveryOpts.addressGapLimit == 0 || discoveryOpts.accountGapLimit == 0 {
+		return nil, ierrors.New("gap limits need to be greater than zero")
+	}
+
+	coinType, err := keyManager.CoinType()
+	if err != nil {
+		return nil, err
+	}
+
+	usedPaths := make([]bip32path.Path, 0)
+
+	var unusedAccounts uint32
+	for account := uint32(0); unusedAccounts < discoveryOpts.accountGapLimit; account++ {
+		accountUsed := false
+
+		for _, change := range discoveryOpts.changeIndices {
+			var unusedAddresses uint32
+			for addressIndex := uint32(0); unusedAddresses < discoveryOpts.addressGapLimit; addressIndex++ {
+				path := BIP44Path(coinType, account, change, addressIndex)
+
+				used, err := isPathUsed(ctx, keyManager.ForPath(path), indexer, netPrefix)
+				if err != nil {
+					return nil, ierrors.Wrapf(err, "failed to check path %s", path)
+				}
+
+				if !used {
+					unusedAddresses++
+					continue
+				}
+
+				usedPaths = append(usedPaths, path)
+				unusedAddresses = 0
+				accountUsed = true
+			}
+		}
+
+		if accountUsed {
+			unusedAccounts = 0
+		} else {
+			unusedAccounts++
+		}
+	}
+
+	return usedPaths, nil
+}
+
+// isPathUsed returns whether the Ed25519 or implicit account creation address of the given KeyManager holds unspent outputs.
+func isPathUsed(ctx context.Context, keyManager *KeyManager, indexer nodeclient.IndexerClient, netPrefix iotago.NetworkPrefix) (bool, error) {
+	for _, addressType := range []iotago.AddressType{iotago.AddressEd25519, iotago.AddressImplicitAccountCreation} {
+		query := &api.OutputsQuery{
+			IndexerCursorParams: api.IndexerCursorParams{
+				PageSize: 1,
+			},
+			IndexerUnlockableByAddressParams: api.IndexerUnlockableByAddressParams{
+				UnlockableByAddressBech32: keyManager.Address(addressType).Bech32(netPrefix),
+			},
+		}
+
+		resultSet, err := indexer.Outputs(ctx, query)
+		if err != nil {
+			return false, err
+		}
+
+		if resultSet.Next() {
+			return true, nil
+		}
+
+		if resultSet.Error != nil {
+			return false, resultSet.Error
+		}
+	}
+
+	return false, nil
+}
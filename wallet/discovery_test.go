@@ -0,0 +1,89 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iotaledger/iota-crypto-demo/pkg/bip32path"
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/wallet"
+)
+
+func TestDiscoverPaths(t *testing.T) {
+	node := newStandInNode(t)
+
+	client, err := nodeclient.New(node.URL)
+	require.NoError(t, err)
+
+	indexer, err := client.Indexer(context.Background())
+	require.NoError(t, err)
+
+	keyManager, err := wallet.NewKeyManagerFromRandom(wallet.DefaultIOTAPath)
+	require.NoError(t, err)
+
+	coinType, err := keyManager.CoinType()
+	require.NoError(t, err)
+	require.EqualValues(t, 4218, coinType)
+
+	addOutput := func(path bip32path.Path, addressType iotago.AddressType) {
+		node.addOutputs(t, 1, &iotago.BasicOutput{
+			Amount:           1_000_000,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: keyManager.ForPath(path).Address(addressType)}},
+		})
+	}
+
+	usedPaths := []bip32path.Path{
+		wallet.BIP44Path(coinType, 0, 0, 2),
+		wallet.BIP44Path(coinType, 0, 0, 6),
+		wallet.BIP44Path(coinType, 0, 1, 0),
+		wallet.BIP44Path(coinType, 1, 0, 4),
+	}
+	addOutput(usedPaths[0], iotago.AddressEd25519)
+	addOutput(usedPaths[1], iotago.AddressEd25519)
+	addOutput(usedPaths[2], iotago.AddressImplicitAccountCreation)
+	addOutput(usedPaths[3], iotago.AddressEd25519)
+
+	// outside the gap limit
+	addOutput(wallet.BIP44Path(coinType, 0, 0, 12), iotago.AddressEd25519)
+	addOutput(wallet.BIP44Path(coinType, 3, 0, 0), iotago.AddressEd25519)
+
+	t.Run("ok - paths", func(t *testing.T) {
+		paths, err := wallet.DiscoverPaths(context.Background(), keyManager, indexer, client.CommittedAPI().ProtocolParameters().Bech32HRP(), wallet.WithDiscoveryAddressGapLimit(5))
+		require.NoError(t, err)
+		require.Equal(t, usedPaths, paths)
+	})
+
+	t.Run("ok - account gap limit", func(t *testing.T) {
+		paths, err := wallet.DiscoverPaths(context.Background(), keyManager, indexer, client.CommittedAPI().ProtocolParameters().Bech32HRP(),
+			wallet.WithDiscoveryAddressGapLimit(5),
+			wallet.WithDiscoveryAccountGapLimit(2),
+			wallet.WithDiscoveryChangeIndices(0),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []bip32path.Path{usedPaths[0], usedPaths[1], usedPaths[3], wallet.BIP44Path(coinType, 3, 0, 0)}, paths)
+	})
+
+	t.Run("ok - wallet", func(t *testing.T) {
+		w, err := wallet.NewWallet(context.Background(), keyManager, client)
+		require.NoError(t, err)
+
+		require.NoError(t, w.Discover(context.Background(), wallet.WithDiscoveryAddressGapLimit(5)))
+		// the path of the key manager is always part of the wallet
+		require.Len(t, w.Paths(), len(usedPaths)+1)
+
+		require.NoError(t, w.Sync(context.Background()))
+		require.Len(t, w.Outputs(), len(usedPaths))
+
+		balance, err := w.Balance(1)
+		require.NoError(t, err)
+		require.EqualValues(t, len(usedPaths)*1_000_000, balance.BaseTokens)
+	})
+
+	t.Run("err - no BIP44 path", func(t *testing.T) {
+		_, err := wallet.DiscoverPaths(context.Background(), keyManager.ForPath(bip32path.Path{0}), indexer, client.CommittedAPI().ProtocolParameters().Bech32HRP())
+		require.Error(t, err)
+	})
+}
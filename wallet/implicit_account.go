@@ -0,0 +1,193 @@
+package wallet
+
+import (
+	"context"
+	"time"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/builder"
+)
+
+var (
+	// ErrNoImplicitAccount gets returned when an output is not an implicit account of the wallet.
+	ErrNoImplicitAccount = ierrors.New("output is not an implicit account of the wallet")
+)
+
+// ImplicitAccountTransition holds the block which transitions an implicit account to an AccountOutput.
+type ImplicitAccountTransition struct {
+	// The ID of the account, which is derived from the output ID of the implicit account.
+	AccountID iotago.AccountID
+	// The transaction which consumes the implicit account and creates the AccountOutput.
+	Transaction *iotago.SignedTransaction
+	// The block which contains the transaction and is issued by the implicit account itself.
+	Block *iotago.Block
+}
+
+// ImplicitAccounts returns the unlocked outputs of the wallet which are owned by
+// one of its implicit account creation addresses, sorted by their ID.
+func (w *Wallet) ImplicitAccounts() []*OwnedOutput {
+	implicitAccounts := make([]*OwnedOutput, 0)
+	for _, output := range w.UnlockedOutputs() {
+		if isImplicitAccount(output) {
+			implicitAccounts = append(implicitAccounts, output)
+		}
+	}
+
+	return implicitAccounts
+}
+
+// ConvertImplicitAccount transitions the implicit account with the given output ID to an AccountOutput
+// with a BlockIssuerFeature that holds the public key hash of the key of the implicit account.
+// The AccountOutput is owned by the Ed25519 address of the same key.
+//
+// The block containing the transaction is issued by the implicit account itself and paid for with its own
+// block issuance credits, the required mana is allotted to the account and the remaining mana is stored in the AccountOutput.
+// The implicit account is locked until the next synchronization of the wallet.
+func (w *Wallet) ConvertImplicitAccount(ctx context.Context, outputID iotago.OutputID) (*ImplicitAccountTransition, error) {
+	blockIssuance, err := w.client.BlockIssuance(ctx)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to get the block issuance info")
+	}
+
+	transition, err := w.BuildImplicitAccountTransition(outputID, blockIssuance)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.LockInputs(transition.Transaction.Transaction); err != nil {
+		return nil, err
+	}
+
+	if _, err := w.client.SubmitBlock(ctx, transition.Block); err != nil {
+		transactionID, idErr := transition.Transaction.Transaction.ID()
+		if idErr == nil {
+			w.UnlockOutputs(transactionID)
+		}
+
+		return nil, ierrors.Wrap(err, "failed to submit the implicit account transition block")
+	}
+
+	return transition, nil
+}
+
+// BuildImplicitAccountTransition builds the signed block which transitions the implicit account with the given output ID
+// to an AccountOutput, based on the given block issuance info, see ConvertImplicitAccount.
+func (w *Wallet) BuildImplicitAccountTransition(outputID iotago.OutputID, blockIssuance *api.IssuanceBlockHeaderResponse) (*ImplicitAccountTransition, error) {
+	w.mutex.RLock()
+	implicitAccount, exists := w.outputs[outputID]
+	w.mutex.RUnlock()
+
+	if !exists || !isImplicitAccount(implicitAccount) {
+		return nil, ierrors.Wrapf(ErrNoImplicitAccount, "output %s", outputID.ToHex())
+	}
+
+	keyManager, exists := w.keyManagerForAddress(implicitAccount.Address)
+	if !exists {
+		return nil, ierrors.Wrapf(ErrNoImplicitAccount, "no key for address %s", implicitAccount.Address)
+	}
+
+	commitment := blockIssuance.LatestCommitment
+	commitmentID, err := commitment.ID()
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to calculate the commitment ID")
+	}
+
+	apiForSlot := w.client.APIForSlot(commitment.Slot)
+	accountID := iotago.AccountIDFromOutputID(outputID)
+	privateKey, publicKey := keyManager.KeyPair()
+
+	accountOutput := &iotago.AccountOutput{
+		Amount:    implicitAccount.Output.BaseTokenAmount(),
+		AccountID: accountID,
+		UnlockConditions: iotago.AccountOutputUnlockConditions{
+			&iotago.AddressUnlockCondition{Address: keyManager.Address(iotago.AddressEd25519)},
+		},
+		Features: iotago.AccountOutputFeatures{
+			&iotago.BlockIssuerFeature{
+				ExpirySlot:      iotago.MaxSlotIndex,
+				BlockIssuerKeys: iotago.NewBlockIssuerKeys(iotago.Ed25519PublicKeyHashBlockIssuerKeyFromPublicKey(publicKey)),
+			},
+		},
+	}
+
+	// the required allotment of the transaction builder covers the block and the payload,
+	// but the block issuer additionally burns mana for the signature of the block.
+	signatureWorkScore, err := (&iotago.Ed25519Signature{}).WorkScore(apiForSlot.ProtocolParameters().WorkScoreParameters())
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to calculate the workscore of the block signature")
+	}
+
+	signatureManaCost, err := iotago.ManaCost(commitment.ReferenceManaCost, signatureWorkScore)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to calculate the mana cost of the block signature")
+	}
+
+	// the implicit account pays for the block with its own block issuance credits,
+	// so the required mana is allotted to itself and the remaining mana is stored in the account.
+	signedTransaction, err := builder.NewTransactionBuilder(apiForSlot).
+		AddInput(&builder.TxInput{
+			UnlockTarget: implicitAccount.Address,
+			InputID:      outputID,
+			Input:        implicitAccount.Output,
+		}).
+		AddCommitmentInput(&iotago.CommitmentInput{CommitmentID: commitmentID}).
+		AddBlockIssuanceCreditInput(&iotago.BlockIssuanceCreditInput{AccountID: accountID}).
+		AddOutput(accountOutput).
+		SetCreationSlot(commitment.Slot).
+		IncreaseAllotment(accountID, signatureManaCost).
+		AllotRequiredManaAndStoreRemainingManaInOutput(commitment.Slot, commitment.ReferenceManaCost, accountID, 0).
+		Build(keyManager.AddressSigner())
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to build the implicit account transition")
+	}
+
+	// the issuing time needs to be after the issuing time of the parents
+	issuingTime := time.Now().UTC()
+	if !issuingTime.After(blockIssuance.LatestParentBlockIssuingTime) {
+		issuingTime = blockIssuance.LatestParentBlockIssuingTime.Add(time.Nanosecond)
+	}
+
+	block, err := builder.NewBasicBlockBuilder(apiForSlot).
+		IssuingTime(issuingTime).
+		SlotCommitmentID(commitmentID).
+		LatestFinalizedSlot(blockIssuance.LatestFinalizedSlot).
+		StrongParents(blockIssuance.StrongParents).
+		WeakParents(blockIssuance.WeakParents).
+		ShallowLikeParents(blockIssuance.ShallowLikeParents).
+		Payload(signedTransaction).
+		CalculateAndSetMaxBurnedMana(commitment.ReferenceManaCost).
+		Sign(accountID, privateKey).
+		Build()
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to build the implicit account transition block")
+	}
+
+	return &ImplicitAccountTransition{
+		AccountID:   accountID,
+		Transaction: signedTransaction,
+		Block:       block,
+	}, nil
+}
+
+// keyManagerForAddress returns the KeyManager of the derivation path of the wallet the given address belongs to.
+func (w *Wallet) keyManagerForAddress(address iotago.Address) (*KeyManager, bool) {
+	for _, path := range w.Paths() {
+		keyManager := w.keyManager.ForPath(path)
+		if keyManager.Address(address.Type()).Equal(address) {
+			return keyManager, true
+		}
+	}
+
+	return nil, false
+}
+
+// isImplicitAccount returns whether the given output is a BasicOutput owned by an implicit account creation address.
+func isImplicitAccount(output *OwnedOutput) bool {
+	if _, isBasicOutput := output.Output.(*iotago.BasicOutput); !isBasicOutput {
+		return false
+	}
+
+	return output.Address.Type() == iotago.AddressImplicitAccountCreation
+}
@@ -0,0 +1,112 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+	"github.com/iotaledger/iota.go/v4/vm"
+	"github.com/iotaledger/iota.go/v4/vm/nova"
+	"github.com/iotaledger/iota.go/v4/wallet"
+)
+
+func TestWalletConvertImplicitAccount(t *testing.T) {
+	node := newStandInNode(t)
+
+	client, err := nodeclient.New(node.URL)
+	require.NoError(t, err)
+
+	keyManager, err := wallet.NewKeyManagerFromRandom(wallet.DefaultIOTAPath)
+	require.NoError(t, err)
+
+	w, err := wallet.NewWallet(context.Background(), keyManager, client)
+	require.NoError(t, err)
+
+	outputIDs := node.addOutputs(t, 10,
+		&iotago.BasicOutput{
+			Amount: 1_000_000,
+			Mana:   1_000_000,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{
+				&iotago.AddressUnlockCondition{Address: keyManager.Address(iotago.AddressImplicitAccountCreation)},
+			},
+		},
+		&iotago.BasicOutput{
+			Amount: 1_000_000,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{
+				&iotago.AddressUnlockCondition{Address: keyManager.Address(iotago.AddressEd25519)},
+			},
+		},
+	)
+	implicitAccountID, basicOutputID := outputIDs[0], outputIDs[1]
+
+	commitment := iotago.NewCommitment(testAPI.Version(), 20, iotago.EmptyCommitmentID, iotago.EmptyIdentifier, 0, 1)
+	node.blockIssuance = &api.IssuanceBlockHeaderResponse{
+		StrongParents: tpkg.SortedRandBlockIDs(1),
+		// the block is issued after its parents, in a slot that is allowed to commit to the latest commitment
+		LatestParentBlockIssuingTime: testAPI.TimeProvider().SlotStartTime(commitment.Slot + testAPI.ProtocolParameters().MinCommittableAge()),
+		LatestFinalizedSlot:          15,
+		LatestCommitment:             commitment,
+	}
+
+	require.NoError(t, w.Sync(context.Background()))
+
+	implicitAccounts := w.ImplicitAccounts()
+	require.Len(t, implicitAccounts, 1)
+	require.Equal(t, implicitAccountID, implicitAccounts[0].OutputID)
+
+	t.Run("err - no implicit account", func(t *testing.T) {
+		_, err := w.ConvertImplicitAccount(context.Background(), basicOutputID)
+		require.ErrorIs(t, err, wallet.ErrNoImplicitAccount)
+
+		_, err = w.ConvertImplicitAccount(context.Background(), tpkg.RandOutputID(0))
+		require.ErrorIs(t, err, wallet.ErrNoImplicitAccount)
+		require.Empty(t, node.blocks)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		transition, err := w.ConvertImplicitAccount(context.Background(), implicitAccountID)
+		require.NoError(t, err)
+		require.Equal(t, iotago.AccountIDFromOutputID(implicitAccountID), transition.AccountID)
+		require.True(t, w.IsLocked(implicitAccountID))
+		require.Empty(t, w.ImplicitAccounts())
+
+		// the block is issued by the implicit account itself
+		require.Len(t, node.blocks, 1)
+		block := node.blocks[0]
+		require.Equal(t, transition.AccountID, block.Header.IssuerID)
+		//nolint:forcetypeassert // we know the payload is a SignedTransaction
+		submittedTransaction := block.Body.(*iotago.BasicBlockBody).Payload.(*iotago.SignedTransaction)
+		require.Equal(t, lo.PanicOnErr(transition.Transaction.ID()), lo.PanicOnErr(submittedTransaction.ID()))
+
+		// the block issuer key of the account is the key of the implicit account
+		//nolint:forcetypeassert // we know the output is an AccountOutput
+		accountOutput := transition.Transaction.Transaction.Outputs[0].(*iotago.AccountOutput)
+		_, publicKey := keyManager.KeyPair()
+		require.Equal(t, transition.AccountID, accountOutput.AccountID)
+		require.True(t, accountOutput.FeatureSet().BlockIssuer().BlockIssuerKeys.Has(iotago.Ed25519PublicKeyHashBlockIssuerKeyFromPublicKey(publicKey)))
+
+		// the transaction is valid and uses the block issuance credits of the implicit account
+		resolvedInputs := vm.ResolvedInputs{
+			InputSet:                    vm.InputSet{implicitAccountID: implicitAccounts[0].Output},
+			BlockIssuanceCreditInputSet: vm.BlockIssuanceCreditInputSet{transition.AccountID: 0},
+			CommitmentInput:             commitment,
+		}
+
+		novaVM := nova.NewVirtualMachine()
+		unlockedIdentities, err := novaVM.ValidateUnlocks(transition.Transaction, resolvedInputs)
+		require.NoError(t, err)
+		_, err = novaVM.Execute(transition.Transaction.Transaction, resolvedInputs, unlockedIdentities)
+		require.NoError(t, err)
+
+		// the required mana is allotted to the account for the block
+		require.Len(t, transition.Transaction.Transaction.Allotments, 1)
+		require.Equal(t, transition.AccountID, transition.Transaction.Transaction.Allotments[0].AccountID)
+		require.EqualValues(t, block.Body.(*iotago.BasicBlockBody).MaxBurnedMana, transition.Transaction.Transaction.Allotments[0].Mana)
+	})
+}
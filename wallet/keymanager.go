@@ -9,6 +9,7 @@ import (
 	"github.com/iotaledger/iota-crypto-demo/pkg/slip10/eddsa"
 
 	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
 	iotago "github.com/iotaledger/iota.go/v4"
 )
 
@@ -17,8 +18,28 @@ const (
 	DefaultShimmerPath = "m/44'/4219'/0'/0'/0'"
 )
 
+const (
+	// bip44Purpose is the purpose of BIP44 paths.
+	bip44Purpose uint32 = 44
+	// hardenedOffset is added to an index to derive a hardened key.
+	hardenedOffset uint32 = 1 << 31
+)
+
+// BIP44Path returns the path "m/44'/coinType'/account'/change'/addressIndex'".
+// All indices are hardened, because SLIP-10 only supports hardened derivation for ed25519.
+func BIP44Path(coinType uint32, account uint32, change uint32, addressIndex uint32) bip32path.Path {
+	return bip32path.Path{
+		bip44Purpose | hardenedOffset,
+		coinType | hardenedOffset,
+		account | hardenedOffset,
+		change | hardenedOffset,
+		addressIndex | hardenedOffset,
+	}
+}
+
 // KeyManager is a hierarchical deterministic key manager.
 // NOTE: The seed is stored in memory and is not protected against memory dumps.
+// Call Close to zero the seed once the KeyManager is not needed anymore, use a Keystore to persist it encrypted.
 type KeyManager struct {
 	seed []byte
 	path bip32path.Path
@@ -35,19 +56,17 @@ func NewKeyManagerFromRandom(path string) (*KeyManager, error) {
 	return NewKeyManager(random.Seed(), path)
 }
 
-// NewKeyManagerFromMnemonic creates a new key manager from a mnemonic.
-func NewKeyManagerFromMnemonic(mnemonic string, path string) (*KeyManager, error) {
-	mnemonicSentence := bip39.ParseMnemonic(mnemonic)
-	if len(mnemonicSentence) != 24 {
-		return nil, ierrors.Errorf("mnemomic contains an invalid sentence length. Mnemonic should be 24 words")
-	}
+// NewKeyManagerFromMnemonic creates a new key manager from a mnemonic with 12, 15, 18, 21 or 24 words.
+// The mnemonic is validated against the BIP39 wordlist and its checksum, see ValidateMnemonic.
+func NewKeyManagerFromMnemonic(mnemonic string, path string, opts ...options.Option[MnemonicOptions]) (*KeyManager, error) {
+	mnemonicOpts := options.Apply(&MnemonicOptions{}, opts)
 
-	seed, err := bip39.MnemonicToSeed(mnemonicSentence, "")
+	mnemonicSentence, err := parseMnemonic(mnemonic)
 	if err != nil {
-		return nil, ierrors.Wrap(err, "failed to convert mnemonic to seed")
+		return nil, err
 	}
 
-	return NewKeyManager(seed, path)
+	return NewKeyManager(mnemonicToSeed(mnemonicSentence, mnemonicOpts.passphrase), path)
 }
 
 // NewKeyManager creates a new key manager.
@@ -80,6 +99,24 @@ func (k *KeyManager) Path() bip32path.Path {
 	return k.path
 }
 
+// CoinType returns the coin type of the BIP44 path of the key manager.
+func (k *KeyManager) CoinType() (uint32, error) {
+	if len(k.path) < 2 || k.path[0] != bip44Purpose|hardenedOffset {
+		return 0, ierrors.Errorf("path %s is not a BIP44 path", k.path)
+	}
+
+	return k.path[1] &^ hardenedOffset, nil
+}
+
+// ForPath returns a key manager for the given path which uses the same seed.
+// Closing either of the key managers zeroes the seed of both.
+func (k *KeyManager) ForPath(path bip32path.Path) *KeyManager {
+	return &KeyManager{
+		seed: k.seed,
+		path: path,
+	}
+}
+
 // Mnemonic returns the mnemonic of the key manager.
 func (k *KeyManager) Mnemonic() bip39.Mnemonic {
 	mnemonic, err := bip39.EntropyToMnemonic(k.seed)
@@ -90,8 +127,18 @@ func (k *KeyManager) Mnemonic() bip39.Mnemonic {
 	return mnemonic
 }
 
+// Close zeroes the seed of the key manager. The key manager must not be used afterward.
+func (k *KeyManager) Close() {
+	zeroBytes(k.seed)
+}
+
 // AddressSigner returns an address signer.
 func (k *KeyManager) AddressSigner() iotago.AddressSigner {
+	return iotago.NewInMemoryAddressSigner(k.addressKeys()...)
+}
+
+// addressKeys returns the address keys of the Ed25519 and the implicit account creation address.
+func (k *KeyManager) addressKeys() []iotago.AddressKeys {
 	privKey, pubKey := k.KeyPair()
 
 	// add both address types for simplicity in tests
@@ -100,7 +147,7 @@ func (k *KeyManager) AddressSigner() iotago.AddressSigner {
 	implicitAccountCreationAddress := iotago.ImplicitAccountCreationAddressFromPubKey(pubKey)
 	implicitAccountCreationAddressKey := iotago.NewAddressKeysForImplicitAccountCreationAddress(implicitAccountCreationAddress, privKey)
 
-	return iotago.NewInMemoryAddressSigner(ed25519AddressKey, implicitAccountCreationAddressKey)
+	return []iotago.AddressKeys{ed25519AddressKey, implicitAccountCreationAddressKey}
 }
 
 // Address calculates an address of the specified type.
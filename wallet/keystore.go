@@ -0,0 +1,489 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/iotaledger/iota-crypto-demo/pkg/bip32path"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+)
+
+// KeystoreVersion is the current version of the encrypted keystore format.
+const KeystoreVersion byte = 1
+
+const (
+	keystoreSaltLength = 32
+	keystoreKeyLength  = 32
+)
+
+// the maximum KDF parameters, which are checked before a key is derived,
+// since the parameters of an encrypted keystore are read from its unauthenticated header.
+const (
+	keystoreMaxScryptLogN    = 20
+	keystoreMaxScryptRP      = 64
+	keystoreMaxArgon2Time    = 16
+	keystoreMaxArgon2Memory  = 4 * 1024 * 1024
+	keystoreMaxArgon2Threads = 64
+)
+
+var (
+	// keystoreMagic is the prefix of every encrypted keystore.
+	keystoreMagic = []byte("iotaks")
+)
+
+var (
+	// ErrKeystoreMalformed gets returned when an encrypted keystore can't be parsed.
+	ErrKeystoreMalformed = ierrors.New("malformed keystore")
+	// ErrKeystoreUnsupportedVersion gets returned when an encrypted keystore has an unknown version.
+	ErrKeystoreUnsupportedVersion = ierrors.New("unsupported keystore version")
+	// ErrKeystoreInvalidPassword gets returned when an encrypted keystore can't be decrypted with the given password
+	// or its content was tampered with.
+	ErrKeystoreInvalidPassword = ierrors.New("invalid keystore password or corrupted keystore")
+	// ErrKeystoreKDFParamsTooHigh gets returned when the KDF parameters of a keystore exceed the supported maximums.
+	ErrKeystoreKDFParamsTooHigh = ierrors.New("keystore kdf parameters too high")
+	// ErrKeystoreClosed gets returned when a closed keystore is used.
+	ErrKeystoreClosed = ierrors.New("keystore is closed")
+)
+
+// KeystoreKDF defines the function used to derive the encryption key from the password.
+type KeystoreKDF byte
+
+const (
+	// KeystoreKDFScrypt derives the encryption key with scrypt.
+	KeystoreKDFScrypt KeystoreKDF = 1
+	// KeystoreKDFArgon2id derives the encryption key with argon2id.
+	KeystoreKDFArgon2id KeystoreKDF = 2
+)
+
+// KeystoreCipher defines the authenticated cipher used to encrypt the content of the keystore.
+type KeystoreCipher byte
+
+const (
+	// KeystoreCipherAES256GCM encrypts the keystore with AES-256 in GCM mode.
+	KeystoreCipherAES256GCM KeystoreCipher = 1
+	// KeystoreCipherXChaCha20Poly1305 encrypts the keystore with XChaCha20-Poly1305.
+	KeystoreCipherXChaCha20Poly1305 KeystoreCipher = 2
+)
+
+// KeystoreOptions defines the options for the encryption of a keystore.
+type KeystoreOptions struct {
+	kdf    KeystoreKDF
+	cipher KeystoreCipher
+
+	scryptLogN uint8
+	scryptR    uint32
+	scryptP    uint32
+
+	argon2Time    uint32
+	argon2Memory  uint32
+	argon2Threads uint8
+}
+
+// WithKeystoreKDF sets the function used to derive the encryption key from the password. Defaults to argon2id.
+func WithKeystoreKDF(kdf KeystoreKDF) options.Option[KeystoreOptions] {
+	return func(o *KeystoreOptions) {
+		o.kdf = kdf
+	}
+}
+
+// WithKeystoreCipher sets the cipher used to encrypt the keystore. Defaults to XChaCha20-Poly1305.
+func WithKeystoreCipher(cipher KeystoreCipher) options.Option[KeystoreOptions] {
+	return func(o *KeystoreOptions) {
+		o.cipher = cipher
+	}
+}
+
+// WithKeystoreScryptParams sets the scrypt cost parameters, where the CPU/memory cost is 2^logN.
+func WithKeystoreScryptParams(logN uint8, r uint32, p uint32) options.Option[KeystoreOptions] {
+	return func(o *KeystoreOptions) {
+		o.scryptLogN = logN
+		o.scryptR = r
+		o.scryptP = p
+	}
+}
+
+// WithKeystoreArgon2idParams sets the argon2id cost parameters, the memory is given in KiB.
+func WithKeystoreArgon2idParams(time uint32, memory uint32, threads uint8) options.Option[KeystoreOptions] {
+	return func(o *KeystoreOptions) {
+		o.argon2Time = time
+		o.argon2Memory = memory
+		o.argon2Threads = threads
+	}
+}
+
+func defaultKeystoreOptions() *KeystoreOptions {
+	return &KeystoreOptions{
+		kdf:           KeystoreKDFArgon2id,
+		cipher:        KeystoreCipherXChaCha20Poly1305,
+		scryptLogN:    15,
+		scryptR:       8,
+		scryptP:       1,
+		argon2Time:    3,
+		argon2Memory:  64 * 1024,
+		argon2Threads: 4,
+	}
+}
+
+// Keystore holds the seed and the derivation paths in use of a wallet.
+// It can be encrypted with a password to persist it on disk.
+//
+// The format of an encrypted keystore is:
+//
+//	magic "iotaks" | version (1 byte) | kdf (1 byte) | kdf params | cipher (1 byte) | salt (32 bytes) | nonce | ciphertext
+//
+// The header in front of the ciphertext is authenticated as additional data.
+type Keystore struct {
+	seed  []byte
+	paths []bip32path.Path
+}
+
+// NewKeystore creates a new Keystore for the seed of the given KeyManager and the given derivation paths.
+// If no paths are given, the path of the KeyManager is used.
+func NewKeystore(keyManager *KeyManager, paths ...bip32path.Path) *Keystore {
+	if len(paths) == 0 {
+		paths = []bip32path.Path{keyManager.Path()}
+	}
+
+	return &Keystore{
+		seed:  append([]byte(nil), keyManager.seed...),
+		paths: append([]bip32path.Path(nil), paths...),
+	}
+}
+
+// Paths returns the derivation paths of the keystore.
+func (k *Keystore) Paths() []bip32path.Path {
+	return append([]bip32path.Path(nil), k.paths...)
+}
+
+// KeyManager returns a new KeyManager for the first derivation path of the keystore.
+// The KeyManager holds a copy of the seed and needs to be closed separately.
+func (k *Keystore) KeyManager() (*KeyManager, error) {
+	if k.seed == nil {
+		return nil, ErrKeystoreClosed
+	}
+
+	return &KeyManager{
+		seed: append([]byte(nil), k.seed...),
+		path: k.paths[0],
+	}, nil
+}
+
+// AddressSigner returns an address signer for the addresses of all derivation paths of the keystore.
+func (k *Keystore) AddressSigner() (iotago.AddressSigner, error) {
+	if k.seed == nil {
+		return nil, ErrKeystoreClosed
+	}
+
+	keyManager := &KeyManager{seed: k.seed}
+
+	addressKeys := make([]iotago.AddressKeys, 0, 2*len(k.paths))
+	for _, path := range k.paths {
+		addressKeys = append(addressKeys, keyManager.ForPath(path).addressKeys()...)
+	}
+
+	return iotago.NewInMemoryAddressSigner(addressKeys...), nil
+}
+
+// Close zeroes the seed of the keystore.
+func (k *Keystore) Close() {
+	zeroBytes(k.seed)
+	k.seed = nil
+}
+
+// Encrypt encrypts the keystore with a key derived from the given password.
+func (k *Keystore) Encrypt(password []byte, opts ...options.Option[KeystoreOptions]) ([]byte, error) {
+	if k.seed == nil {
+		return nil, ErrKeystoreClosed
+	}
+
+	keystoreOpts := options.Apply(defaultKeystoreOptions(), opts)
+
+	header := bytes.NewBuffer(append([]byte(nil), keystoreMagic...))
+	header.WriteByte(KeystoreVersion)
+	header.WriteByte(byte(keystoreOpts.kdf))
+
+	switch keystoreOpts.kdf {
+	case KeystoreKDFScrypt:
+		header.WriteByte(keystoreOpts.scryptLogN)
+		_ = binary.Write(header, binary.LittleEndian, keystoreOpts.scryptR)
+		_ = binary.Write(header, binary.LittleEndian, keystoreOpts.scryptP)
+	case KeystoreKDFArgon2id:
+		_ = binary.Write(header, binary.LittleEndian, keystoreOpts.argon2Time)
+		_ = binary.Write(header, binary.LittleEndian, keystoreOpts.argon2Memory)
+		header.WriteByte(keystoreOpts.argon2Threads)
+	default:
+		return nil, ierrors.Errorf("unknown keystore kdf %d", keystoreOpts.kdf)
+	}
+
+	header.WriteByte(byte(keystoreOpts.cipher))
+
+	salt := make([]byte, keystoreSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, ierrors.Wrap(err, "failed to generate salt")
+	}
+	header.Write(salt)
+
+	key, err := deriveKeystoreKey(keystoreOpts, password, salt)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+
+	aead, err := newKeystoreAEAD(keystoreOpts.cipher, key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, ierrors.Wrap(err, "failed to generate nonce")
+	}
+	header.Write(nonce)
+
+	plaintext := k.plaintext()
+	defer zeroBytes(plaintext)
+
+	return aead.Seal(header.Bytes(), nonce, plaintext, header.Bytes()), nil
+}
+
+// WriteFile encrypts the keystore with a key derived from the given password and writes it to the given file.
+func (k *Keystore) WriteFile(filePath string, password []byte, opts ...options.Option[KeystoreOptions]) error {
+	data, err := k.Encrypt(password, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, data, 0o600); err != nil {
+		return ierrors.Wrapf(err, "failed to write keystore file %s", filePath)
+	}
+
+	return nil
+}
+
+// plaintext serializes the seed and the paths of the keystore.
+func (k *Keystore) plaintext() []byte {
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(k.seed)))
+	buf.Write(k.seed)
+
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(k.paths)))
+	for _, path := range k.paths {
+		buf.WriteByte(byte(len(path)))
+		for _, index := range path {
+			_ = binary.Write(buf, binary.LittleEndian, index)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// DecryptKeystore decrypts the given encrypted keystore with a key derived from the given password.
+func DecryptKeystore(data []byte, password []byte) (*Keystore, error) {
+	reader := bytes.NewReader(data)
+
+	magic := make([]byte, len(keystoreMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || !bytes.Equal(magic, keystoreMagic) {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "invalid magic")
+	}
+
+	var version byte
+	if err := binary.Read(reader, binary.LittleEndian, &version); err != nil {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read version")
+	}
+	if version != KeystoreVersion {
+		return nil, ierrors.Wrapf(ErrKeystoreUnsupportedVersion, "version %d", version)
+	}
+
+	keystoreOpts := &KeystoreOptions{}
+	if err := binary.Read(reader, binary.LittleEndian, &keystoreOpts.kdf); err != nil {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read kdf")
+	}
+
+	var kdfParams []any
+	switch keystoreOpts.kdf {
+	case KeystoreKDFScrypt:
+		kdfParams = []any{&keystoreOpts.scryptLogN, &keystoreOpts.scryptR, &keystoreOpts.scryptP}
+	case KeystoreKDFArgon2id:
+		kdfParams = []any{&keystoreOpts.argon2Time, &keystoreOpts.argon2Memory, &keystoreOpts.argon2Threads}
+	default:
+		return nil, ierrors.Wrapf(ErrKeystoreMalformed, "unknown kdf %d", keystoreOpts.kdf)
+	}
+
+	for _, param := range append(kdfParams, &keystoreOpts.cipher) {
+		if err := binary.Read(reader, binary.LittleEndian, param); err != nil {
+			return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read kdf parameters")
+		}
+	}
+
+	salt := make([]byte, keystoreSaltLength)
+	if _, err := io.ReadFull(reader, salt); err != nil {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read salt")
+	}
+
+	key, err := deriveKeystoreKey(keystoreOpts, password, salt)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+
+	aead, err := newKeystoreAEAD(keystoreOpts.cipher, key)
+	if err != nil {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, err.Error())
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(reader, nonce); err != nil {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read nonce")
+	}
+
+	headerLength := len(data) - reader.Len()
+	plaintext, err := aead.Open(nil, nonce, data[headerLength:], data[:headerLength])
+	if err != nil {
+		return nil, ErrKeystoreInvalidPassword
+	}
+	defer zeroBytes(plaintext)
+
+	return keystoreFromPlaintext(plaintext)
+}
+
+// ReadKeystoreFile reads the encrypted keystore from the given file and decrypts it with a key derived from the given password.
+func ReadKeystoreFile(filePath string, password []byte) (*Keystore, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, ierrors.Wrapf(err, "failed to read keystore file %s", filePath)
+	}
+
+	return DecryptKeystore(data, password)
+}
+
+// ChangeKeystorePassword decrypts the given encrypted keystore with the old password
+// and encrypts it again with a key derived from the new password and a new salt.
+func ChangeKeystorePassword(data []byte, oldPassword []byte, newPassword []byte, opts ...options.Option[KeystoreOptions]) ([]byte, error) {
+	keystore, err := DecryptKeystore(data, oldPassword)
+	if err != nil {
+		return nil, err
+	}
+	defer keystore.Close()
+
+	return keystore.Encrypt(newPassword, opts...)
+}
+
+// keystoreFromPlaintext deserializes the seed and the paths of a keystore.
+func keystoreFromPlaintext(plaintext []byte) (*Keystore, error) {
+	reader := bytes.NewReader(plaintext)
+
+	var seedLength uint16
+	if err := binary.Read(reader, binary.LittleEndian, &seedLength); err != nil {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read seed length")
+	}
+
+	seed := make([]byte, seedLength)
+	if _, err := io.ReadFull(reader, seed); err != nil {
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read seed")
+	}
+
+	var pathCount uint16
+	if err := binary.Read(reader, binary.LittleEndian, &pathCount); err != nil {
+		zeroBytes(seed)
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read path count")
+	}
+
+	paths := make([]bip32path.Path, 0, pathCount)
+	for i := 0; i < int(pathCount); i++ {
+		var pathLength byte
+		if err := binary.Read(reader, binary.LittleEndian, &pathLength); err != nil {
+			zeroBytes(seed)
+			return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read path length")
+		}
+
+		path := make(bip32path.Path, pathLength)
+		if err := binary.Read(reader, binary.LittleEndian, []uint32(path)); err != nil {
+			zeroBytes(seed)
+			return nil, ierrors.Wrap(ErrKeystoreMalformed, "failed to read path")
+		}
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		zeroBytes(seed)
+		return nil, ierrors.Wrap(ErrKeystoreMalformed, "no derivation path")
+	}
+
+	return &Keystore{
+		seed:  seed,
+		paths: paths,
+	}, nil
+}
+
+// deriveKeystoreKey derives the encryption key from the password with the kdf of the given options.
+// KDF parameters above the maximums are rejected, so a crafted keystore header can't exhaust memory or CPU.
+func deriveKeystoreKey(keystoreOpts *KeystoreOptions, password []byte, salt []byte) ([]byte, error) {
+	switch keystoreOpts.kdf {
+	case KeystoreKDFScrypt:
+		if keystoreOpts.scryptLogN > keystoreMaxScryptLogN {
+			return nil, ierrors.Wrapf(ErrKeystoreKDFParamsTooHigh, "scrypt logN %d exceeds %d", keystoreOpts.scryptLogN, keystoreMaxScryptLogN)
+		}
+		if uint64(keystoreOpts.scryptR)*uint64(keystoreOpts.scryptP) > keystoreMaxScryptRP {
+			return nil, ierrors.Wrapf(ErrKeystoreKDFParamsTooHigh, "scrypt r*p %d exceeds %d", uint64(keystoreOpts.scryptR)*uint64(keystoreOpts.scryptP), keystoreMaxScryptRP)
+		}
+
+		key, err := scrypt.Key(password, salt, 1<<keystoreOpts.scryptLogN, int(keystoreOpts.scryptR), int(keystoreOpts.scryptP), keystoreKeyLength)
+		if err != nil {
+			return nil, ierrors.Wrap(err, "failed to derive key with scrypt")
+		}
+
+		return key, nil
+	case KeystoreKDFArgon2id:
+		if keystoreOpts.argon2Time == 0 || keystoreOpts.argon2Threads == 0 {
+			return nil, ierrors.New("invalid argon2id parameters")
+		}
+		if keystoreOpts.argon2Time > keystoreMaxArgon2Time {
+			return nil, ierrors.Wrapf(ErrKeystoreKDFParamsTooHigh, "argon2id time %d exceeds %d", keystoreOpts.argon2Time, keystoreMaxArgon2Time)
+		}
+		if keystoreOpts.argon2Memory > keystoreMaxArgon2Memory {
+			return nil, ierrors.Wrapf(ErrKeystoreKDFParamsTooHigh, "argon2id memory %d KiB exceeds %d KiB", keystoreOpts.argon2Memory, keystoreMaxArgon2Memory)
+		}
+		if keystoreOpts.argon2Threads > keystoreMaxArgon2Threads {
+			return nil, ierrors.Wrapf(ErrKeystoreKDFParamsTooHigh, "argon2id threads %d exceeds %d", keystoreOpts.argon2Threads, keystoreMaxArgon2Threads)
+		}
+
+		return argon2.IDKey(password, salt, keystoreOpts.argon2Time, keystoreOpts.argon2Memory, keystoreOpts.argon2Threads, keystoreKeyLength), nil
+	default:
+		return nil, ierrors.Errorf("unknown keystore kdf %d", keystoreOpts.kdf)
+	}
+}
+
+// newKeystoreAEAD creates the authenticated cipher used to encrypt the keystore.
+func newKeystoreAEAD(keystoreCipher KeystoreCipher, key []byte) (cipher.AEAD, error) {
+	switch keystoreCipher {
+	case KeystoreCipherAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, ierrors.Wrap(err, "failed to create AES cipher")
+		}
+
+		return cipher.NewGCM(block)
+	case KeystoreCipherXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, ierrors.Errorf("unknown keystore cipher %d", keystoreCipher)
+	}
+}
+
+// zeroBytes overwrites the given bytes with zeros.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,163 @@
+//nolint:scopelint
+package wallet_test
+
+import (
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/iotaledger/iota-crypto-demo/pkg/bip32path"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/wallet"
+)
+
+// cheap cost parameters to keep the tests fast.
+var cheapKeystoreParams = []options.Option[wallet.KeystoreOptions]{
+	wallet.WithKeystoreScryptParams(10, 8, 1),
+	wallet.WithKeystoreArgon2idParams(1, 1024, 1),
+}
+
+func TestKeystore(t *testing.T) {
+	keyManager, err := wallet.NewKeyManagerFromRandom(wallet.DefaultIOTAPath)
+	require.NoError(t, err)
+
+	coinType, err := keyManager.CoinType()
+	require.NoError(t, err)
+	paths := []bip32path.Path{keyManager.Path(), wallet.BIP44Path(coinType, 0, 0, 1)}
+
+	password := []byte("correct horse battery staple")
+
+	for _, kdf := range []wallet.KeystoreKDF{wallet.KeystoreKDFScrypt, wallet.KeystoreKDFArgon2id} {
+		for _, keystoreCipher := range []wallet.KeystoreCipher{wallet.KeystoreCipherAES256GCM, wallet.KeystoreCipherXChaCha20Poly1305} {
+			opts := append([]options.Option[wallet.KeystoreOptions]{wallet.WithKeystoreKDF(kdf), wallet.WithKeystoreCipher(keystoreCipher)}, cheapKeystoreParams...)
+
+			data, err := wallet.NewKeystore(keyManager, paths...).Encrypt(password, opts...)
+			require.NoError(t, err)
+
+			keystore, err := wallet.DecryptKeystore(data, password)
+			require.NoError(t, err)
+			require.Equal(t, paths, keystore.Paths())
+
+			restoredKeyManager, err := keystore.KeyManager()
+			require.NoError(t, err)
+			require.Equal(t, keyManager.Address(iotago.AddressEd25519), restoredKeyManager.Address(iotago.AddressEd25519))
+
+			addressSigner, err := keystore.AddressSigner()
+			require.NoError(t, err)
+			_, err = addressSigner.Sign(keyManager.ForPath(paths[1]).Address(iotago.AddressEd25519), []byte("message"))
+			require.NoError(t, err)
+
+			_, err = wallet.DecryptKeystore(data, []byte("wrong password"))
+			require.ErrorIs(t, err, wallet.ErrKeystoreInvalidPassword)
+		}
+	}
+
+	t.Run("ok - change password", func(t *testing.T) {
+		data, err := wallet.NewKeystore(keyManager).Encrypt(password, cheapKeystoreParams...)
+		require.NoError(t, err)
+
+		newPassword := []byte("new password")
+		changedData, err := wallet.ChangeKeystorePassword(data, password, newPassword, cheapKeystoreParams...)
+		require.NoError(t, err)
+
+		_, err = wallet.DecryptKeystore(changedData, password)
+		require.ErrorIs(t, err, wallet.ErrKeystoreInvalidPassword)
+
+		keystore, err := wallet.DecryptKeystore(changedData, newPassword)
+		require.NoError(t, err)
+		require.Equal(t, []bip32path.Path{keyManager.Path()}, keystore.Paths())
+
+		_, err = wallet.ChangeKeystorePassword(data, []byte("wrong password"), newPassword, cheapKeystoreParams...)
+		require.ErrorIs(t, err, wallet.ErrKeystoreInvalidPassword)
+	})
+
+	t.Run("ok - file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "wallet.keystore")
+		require.NoError(t, wallet.NewKeystore(keyManager).WriteFile(filePath, password, cheapKeystoreParams...))
+
+		keystore, err := wallet.ReadKeystoreFile(filePath, password)
+		require.NoError(t, err)
+
+		restoredKeyManager, err := keystore.KeyManager()
+		require.NoError(t, err)
+		require.Equal(t, keyManager.Address(iotago.AddressEd25519), restoredKeyManager.Address(iotago.AddressEd25519))
+	})
+
+	t.Run("ok - close zeroes the seed", func(t *testing.T) {
+		keystore := wallet.NewKeystore(keyManager)
+		keystore.Close()
+
+		_, err := keystore.KeyManager()
+		require.ErrorIs(t, err, wallet.ErrKeystoreClosed)
+		_, err = keystore.Encrypt(password)
+		require.ErrorIs(t, err, wallet.ErrKeystoreClosed)
+
+		seed := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}
+		seedKeyManager, err := wallet.NewKeyManager(seed, wallet.DefaultIOTAPath)
+		require.NoError(t, err)
+		seedKeyManager.Close()
+		require.Equal(t, make([]byte, 32), seed)
+	})
+
+	t.Run("err - tampered header", func(t *testing.T) {
+		data, err := wallet.NewKeystore(keyManager).Encrypt(password, append(cheapKeystoreParams, wallet.WithKeystoreKDF(wallet.KeystoreKDFScrypt))...)
+		require.NoError(t, err)
+
+		// flip a bit of the salt, which is authenticated as additional data
+		tampered := append([]byte(nil), data...)
+		tampered[20] ^= 0x01
+
+		_, err = wallet.DecryptKeystore(tampered, password)
+		require.ErrorIs(t, err, wallet.ErrKeystoreInvalidPassword)
+	})
+
+	t.Run("err - unsupported version", func(t *testing.T) {
+		data, err := wallet.NewKeystore(keyManager).Encrypt(password, cheapKeystoreParams...)
+		require.NoError(t, err)
+
+		data[len("iotaks")] = wallet.KeystoreVersion + 1
+		_, err = wallet.DecryptKeystore(data, password)
+		require.ErrorIs(t, err, wallet.ErrKeystoreUnsupportedVersion)
+	})
+
+	t.Run("err - kdf parameters too high", func(t *testing.T) {
+		// the kdf parameters follow the magic, the version and the kdf
+		const kdfParamsOffset = len("iotaks") + 2
+
+		scryptData, err := wallet.NewKeystore(keyManager).Encrypt(password, append(cheapKeystoreParams, wallet.WithKeystoreKDF(wallet.KeystoreKDFScrypt))...)
+		require.NoError(t, err)
+
+		argon2Data, err := wallet.NewKeystore(keyManager).Encrypt(password, append(cheapKeystoreParams, wallet.WithKeystoreKDF(wallet.KeystoreKDFArgon2id))...)
+		require.NoError(t, err)
+
+		tamper := func(data []byte, modify func(kdfParams []byte)) []byte {
+			tampered := append([]byte(nil), data...)
+			modify(tampered[kdfParamsOffset:])
+
+			return tampered
+		}
+
+		for name, data := range map[string][]byte{
+			"scrypt logN":   tamper(scryptData, func(kdfParams []byte) { kdfParams[0] = 63 }),
+			"scrypt r":      tamper(scryptData, func(kdfParams []byte) { binary.LittleEndian.PutUint32(kdfParams[1:], math.MaxUint32) }),
+			"scrypt p":      tamper(scryptData, func(kdfParams []byte) { binary.LittleEndian.PutUint32(kdfParams[5:], math.MaxUint32) }),
+			"argon2 time":   tamper(argon2Data, func(kdfParams []byte) { binary.LittleEndian.PutUint32(kdfParams[0:], math.MaxUint32) }),
+			"argon2 memory": tamper(argon2Data, func(kdfParams []byte) { binary.LittleEndian.PutUint32(kdfParams[4:], math.MaxUint32) }),
+		} {
+			_, err := wallet.DecryptKeystore(data, password)
+			require.ErrorIs(t, err, wallet.ErrKeystoreKDFParamsTooHigh, name)
+		}
+
+		_, err = wallet.NewKeystore(keyManager).Encrypt(password, wallet.WithKeystoreArgon2idParams(1, 8*1024*1024, 1))
+		require.ErrorIs(t, err, wallet.ErrKeystoreKDFParamsTooHigh)
+	})
+
+	t.Run("err - malformed", func(t *testing.T) {
+		_, err := wallet.DecryptKeystore([]byte("not a keystore"), password)
+		require.ErrorIs(t, err, wallet.ErrKeystoreMalformed)
+	})
+}
@@ -0,0 +1,159 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/iotaledger/iota-crypto-demo/pkg/bip39"
+
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+)
+
+var (
+	// ErrInvalidMnemonicLength gets returned when a mnemonic doesn't consist of 12, 15, 18, 21 or 24 words.
+	ErrInvalidMnemonicLength = ierrors.New("invalid mnemonic length")
+	// ErrInvalidMnemonicWord gets returned when a mnemonic contains a word which is not part of the BIP39 wordlist.
+	ErrInvalidMnemonicWord = ierrors.New("invalid mnemonic word")
+	// ErrInvalidMnemonicChecksum gets returned when the checksum of a mnemonic is invalid.
+	ErrInvalidMnemonicChecksum = ierrors.New("invalid mnemonic checksum")
+)
+
+// validMnemonicLengths are the word counts of the BIP39 entropy sizes from 128 to 256 bits.
+var validMnemonicLengths = []int{12, 15, 18, 21, 24}
+
+const (
+	// bip39WordlistSize is the amount of words in a BIP39 wordlist.
+	bip39WordlistSize = 2048
+	// bip39BitsPerWord is the amount of bits encoded by a single word.
+	bip39BitsPerWord = 11
+	// bip39EntropyBitsPerChecksumBit is the amount of entropy bits covered by a single checksum bit.
+	bip39EntropyBitsPerChecksumBit = 32
+	// bip39SeedIterations is the amount of PBKDF2 iterations used to derive the seed from a mnemonic.
+	bip39SeedIterations = 2048
+	// bip39SeedSize is the size of the seed derived from a mnemonic.
+	bip39SeedSize = 64
+)
+
+var (
+	wordIndicesOnce sync.Once
+	wordIndices     map[string]int
+)
+
+// MnemonicOptions defines the options for the import of a mnemonic.
+type MnemonicOptions struct {
+	passphrase string
+}
+
+// WithMnemonicPassphrase sets the optional BIP39 passphrase, sometimes called the "25th word", used to derive the seed.
+func WithMnemonicPassphrase(passphrase string) options.Option[MnemonicOptions] {
+	return func(o *MnemonicOptions) {
+		o.passphrase = passphrase
+	}
+}
+
+// ValidateMnemonic checks that the given mnemonic consists of a valid amount of words of the BIP39 wordlist
+// and that its checksum is valid. The returned error names the offending word if there is one.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := parseMnemonic(mnemonic)
+
+	return err
+}
+
+// parseMnemonic parses and validates the given mnemonic.
+func parseMnemonic(mnemonic string) (bip39.Mnemonic, error) {
+	mnemonicSentence := bip39.ParseMnemonic(mnemonic)
+
+	validLength := false
+	for _, length := range validMnemonicLengths {
+		if len(mnemonicSentence) == length {
+			validLength = true
+			break
+		}
+	}
+	if !validLength {
+		return nil, ierrors.Wrapf(ErrInvalidMnemonicLength, "mnemonic contains %d words, but it should contain 12, 15, 18, 21 or 24 words", len(mnemonicSentence))
+	}
+
+	indices := bip39WordIndices()
+	for i, word := range mnemonicSentence {
+		if _, exists := indices[word]; !exists {
+			if lowerCaseWord := strings.ToLower(word); lowerCaseWord != word {
+				if _, exists := indices[lowerCaseWord]; exists {
+					return nil, ierrors.Wrapf(ErrInvalidMnemonicWord, "word %d %q is not part of the BIP39 wordlist, use lower case letters", i+1, word)
+				}
+			}
+
+			return nil, ierrors.Wrapf(ErrInvalidMnemonicWord, "word %d %q is not part of the BIP39 wordlist", i+1, word)
+		}
+	}
+
+	if !validMnemonicChecksum(mnemonicSentence, indices) {
+		return nil, ierrors.Wrapf(ErrInvalidMnemonicChecksum, "the last word %q does not match the checksum of the mnemonic", mnemonicSentence[len(mnemonicSentence)-1])
+	}
+
+	return mnemonicSentence, nil
+}
+
+// validMnemonicChecksum checks the checksum of a mnemonic of known words.
+// The words encode the entropy followed by the first bits of its SHA256 hash, one bit per 32 bits of entropy.
+// The checksum is checked here instead of by bip39.MnemonicToEntropy, which pads the decoded entropy at the wrong end
+// and therefore rejects valid mnemonics whose entropy starts with a zero byte.
+func validMnemonicChecksum(mnemonicSentence bip39.Mnemonic, indices map[string]int) bool {
+	checksumBits := len(mnemonicSentence) * bip39BitsPerWord / (bip39EntropyBitsPerChecksumBit + 1)
+	entropyBytes := checksumBits * bip39EntropyBitsPerChecksumBit / 8
+
+	// the checksum has at most 8 bits, so it fits into the byte following the entropy
+	decoded := make([]byte, entropyBytes+1)
+	for i, word := range mnemonicSentence {
+		index := indices[word]
+		for bit := 0; bit < bip39BitsPerWord; bit++ {
+			if index&(1<<(bip39BitsPerWord-1-bit)) == 0 {
+				continue
+			}
+
+			position := i*bip39BitsPerWord + bit
+			decoded[position/8] |= 0x80 >> (position % 8)
+		}
+	}
+
+	hash := sha256.Sum256(decoded[:entropyBytes])
+
+	return hash[0]>>(8-checksumBits) == decoded[entropyBytes]>>(8-checksumBits)
+}
+
+// mnemonicToSeed derives the BIP39 seed of a validated mnemonic and the given passphrase.
+// bip39.MnemonicToSeed can't be used, since it validates the mnemonic by bip39.MnemonicToEntropy, see validMnemonicChecksum.
+func mnemonicToSeed(mnemonicSentence bip39.Mnemonic, passphrase string) []byte {
+	return pbkdf2.Key([]byte(mnemonicSentence.String()), []byte("mnemonic"+norm.NFKD.String(passphrase)), bip39SeedIterations, bip39SeedSize, sha512.New)
+}
+
+// bip39WordIndices returns the indices of the words of the BIP39 wordlist in use at the first call.
+// The wordlist is derived by encoding every possible 11 bit value as the first word of a mnemonic.
+func bip39WordIndices() map[string]int {
+	wordIndicesOnce.Do(func() {
+		wordIndices = make(map[string]int, bip39WordlistSize)
+
+		// the smallest entropy size of 128 bits
+		entropy := make([]byte, 16)
+		for i := 0; i < bip39WordlistSize; i++ {
+			// the first word is encoded by the 11 most significant bits
+			entropy[0] = byte(i >> (bip39BitsPerWord - 8))
+			entropy[1] = byte(i << (16 - bip39BitsPerWord))
+
+			mnemonic, err := bip39.EntropyToMnemonic(entropy)
+			if err != nil {
+				panic(ierrors.Wrap(err, "failed to derive the BIP39 wordlist"))
+			}
+
+			wordIndices[mnemonic[0]] = i
+		}
+	})
+
+	return wordIndices
+}
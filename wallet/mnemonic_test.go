@@ -0,0 +1,107 @@
+//nolint:scopelint
+package wallet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/hexutil"
+	"github.com/iotaledger/iota.go/v4/wallet"
+)
+
+func TestNewKeyManagerFromMnemonic(t *testing.T) {
+	// test vectors of the BIP39 reference implementation for 12, 18 and 24 words,
+	// and vectors derived the same way for 15 and 21 words and for entropy starting with a zero byte
+	type vector struct {
+		name     string
+		mnemonic string
+		seed     string
+	}
+
+	vectors := []vector{
+		{
+			name:     "12 words",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "0xc55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			name:     "12 words - entropy 0x7f7f...",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			seed:     "0x2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			name:     "12 words - entropy starting with a zero byte",
+			mnemonic: "abstract zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo young",
+			seed:     "0xad7401a0655bf2879b28d96f03dcc132619b06655722a593157868ff86bd71c1a6d9bd1c3d3cc7914310d2d842a2c8947e902cd104ff071444211665200cf45b",
+		},
+		{
+			name:     "15 words - entropy starting with a zero byte",
+			mnemonic: "above advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor adjust",
+			seed:     "0x1770592556cea04f0e4298e8de1e51e86cbc1c67e9ecc9c3725f7cdb850d206862c4b5d517cc508518a03c2015faa32780e55b530736118555dd0a869c9bdde8",
+		},
+		{
+			name:     "18 words",
+			mnemonic: "gravity machine north sort system female filter attitude volume fold club stay feature office ecology stable narrow fog",
+			seed:     "0x628c3827a8823298ee685db84f55caa34b5cc195a778e52d45f59bcf75aba68e4d7590e101dc414bc1bbd5737666fbbef35d1f1903953b66624f910feef245ac",
+		},
+		{
+			name:     "21 words - entropy starting with a zero byte",
+			mnemonic: "about winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal winner thank year vault",
+			seed:     "0x4216c8d31591884f517912f4d85b7a26fef1e99b69f90507d81e49c4048755eb632d2f99de0b7c93fe46dafc9f54d682c8abede38fe40d469fa4653cce496085",
+		},
+		{
+			name:     "24 words",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			seed:     "0xbda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+		{
+			name:     "24 words - entropy 0x066d...",
+			mnemonic: "all hour make first leader extend hole alien behind guard gospel lava path output census museum junior mass reopen famous sing advance salt reform",
+			seed:     "0x26e975ec644423f4a4c4f4215ef09b4bd7ef924e85d1d17c4cf3f136c2863cf6df0a475045652c57eb5fb41513ca2a2d67722b77e954b4b3fc11f7590449191d",
+		},
+	}
+
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			keyManager, err := wallet.NewKeyManagerFromMnemonic(v.mnemonic, wallet.DefaultIOTAPath, wallet.WithMnemonicPassphrase("TREZOR"))
+			require.NoError(t, err)
+
+			expectedKeyManager, err := wallet.NewKeyManager(lo.PanicOnErr(hexutil.DecodeHex(v.seed)), wallet.DefaultIOTAPath)
+			require.NoError(t, err)
+			require.Equal(t, expectedKeyManager.Address(iotago.AddressEd25519), keyManager.Address(iotago.AddressEd25519))
+
+			// the passphrase changes the seed
+			withoutPassphrase, err := wallet.NewKeyManagerFromMnemonic(v.mnemonic, wallet.DefaultIOTAPath)
+			require.NoError(t, err)
+			require.NotEqual(t, keyManager.Address(iotago.AddressEd25519), withoutPassphrase.Address(iotago.AddressEd25519))
+		})
+	}
+
+	t.Run("err - invalid length", func(t *testing.T) {
+		_, err := wallet.NewKeyManagerFromMnemonic(strings.Repeat("abandon ", 13), wallet.DefaultIOTAPath)
+		require.ErrorIs(t, err, wallet.ErrInvalidMnemonicLength)
+		require.ErrorContains(t, err, "13 words")
+	})
+
+	t.Run("err - unknown word", func(t *testing.T) {
+		_, err := wallet.NewKeyManagerFromMnemonic("abandon abandon abandon abandon abandon abandonn abandon abandon abandon abandon abandon about", wallet.DefaultIOTAPath)
+		require.ErrorIs(t, err, wallet.ErrInvalidMnemonicWord)
+		require.ErrorContains(t, err, `word 6 "abandonn"`)
+	})
+
+	t.Run("err - upper case word", func(t *testing.T) {
+		err := wallet.ValidateMnemonic("abandon abandon abandon Abandon abandon abandon abandon abandon abandon abandon abandon about")
+		require.ErrorIs(t, err, wallet.ErrInvalidMnemonicWord)
+		require.ErrorContains(t, err, `word 4 "Abandon"`)
+		require.ErrorContains(t, err, "lower case")
+	})
+
+	t.Run("err - invalid checksum", func(t *testing.T) {
+		err := wallet.ValidateMnemonic(strings.Repeat("abandon ", 12))
+		require.ErrorIs(t, err, wallet.ErrInvalidMnemonicChecksum)
+	})
+}
@@ -0,0 +1,599 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/iotaledger/iota-crypto-demo/pkg/bip32path"
+
+	"github.com/iotaledger/hive.go/core/safemath"
+	"github.com/iotaledger/hive.go/ierrors"
+	"github.com/iotaledger/hive.go/runtime/options"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/builder"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+)
+
+var (
+	// ErrOutputNotOwned gets returned when an output is not owned by the wallet.
+	ErrOutputNotOwned = ierrors.New("output not owned by the wallet")
+	// ErrOutputLocked gets returned when an output is already used in a pending transaction.
+	ErrOutputLocked = ierrors.New("output is locked by a pending transaction")
+)
+
+// OwnedOutput is an unspent output which can be unlocked by one of the addresses of the Wallet.
+type OwnedOutput struct {
+	// The ID of the output.
+	OutputID iotago.OutputID
+	// The output itself.
+	Output iotago.Output
+	// The address of the wallet the output is unlockable by.
+	Address iotago.Address
+}
+
+// Balance holds the funds of a Wallet.
+type Balance struct {
+	// The sum of the base tokens.
+	BaseTokens iotago.BaseToken
+	// The sum of the stored and potential mana, decayed to the target slot.
+	Mana iotago.Mana
+	// The sum of the native tokens.
+	NativeTokens iotago.NativeTokenSum
+	// The IDs of the owned NFTs.
+	NFTs []iotago.NFTID
+	// The IDs of the owned accounts.
+	Accounts []iotago.AccountID
+}
+
+// Options defines the options for the Wallet.
+type Options struct {
+	syncErrorHandler func(err error)
+}
+
+// WithSyncErrorHandler sets the handler which is called with errors that occur
+// while the Wallet is synchronized in the background, see Wallet.Listen.
+func WithSyncErrorHandler(handler func(err error)) options.Option[Options] {
+	return func(o *Options) {
+		o.syncErrorHandler = handler
+	}
+}
+
+// Wallet is a stateful UTXO wallet which keeps track of the outputs owned by the addresses of a KeyManager.
+// The owned outputs are synchronized through the indexer of a node and outputs used in pending
+// transactions can be locked, so they are not used in several transactions at once.
+type Wallet struct {
+	keyManager *KeyManager
+	client     *nodeclient.Client
+	indexer    nodeclient.IndexerClient
+	opts       *Options
+
+	mutex sync.RWMutex
+	// the derivation paths of the addresses of the wallet.
+	paths []bip32path.Path
+	// the unspent outputs owned by the wallet.
+	outputs map[iotago.OutputID]*OwnedOutput
+	// the outputs used in pending transactions.
+	lockedOutputs map[iotago.OutputID]iotago.TransactionID
+	// the committed slot of the last synchronization.
+	syncedSlot iotago.SlotIndex
+}
+
+// NewWallet creates a new Wallet for the addresses of the given KeyManager.
+// Further derivation paths can be added with Wallet.AddPaths or discovered with Wallet.Discover.
+// Returns nodeclient.ErrIndexerPluginNotAvailable if the node does not support the indexer.
+func NewWallet(ctx context.Context, keyManager *KeyManager, client *nodeclient.Client, opts ...options.Option[Options]) (*Wallet, error) {
+	indexer, err := client.Indexer(ctx)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "failed to get the indexer client")
+	}
+
+	return &Wallet{
+		keyManager:    keyManager,
+		client:        client,
+		indexer:       indexer,
+		paths:         []bip32path.Path{keyManager.Path()},
+		outputs:       make(map[iotago.OutputID]*OwnedOutput),
+		lockedOutputs: make(map[iotago.OutputID]iotago.TransactionID),
+		opts: options.Apply(&Options{
+			syncErrorHandler: func(error) {},
+		}, opts),
+	}, nil
+}
+
+// KeyManager returns the KeyManager of the wallet.
+func (w *Wallet) KeyManager() *KeyManager {
+	return w.keyManager
+}
+
+// Paths returns the derivation paths of the addresses of the wallet.
+func (w *Wallet) Paths() []bip32path.Path {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return append([]bip32path.Path(nil), w.paths...)
+}
+
+// AddPaths adds the given derivation paths to the wallet. Known paths are ignored.
+func (w *Wallet) AddPaths(paths ...bip32path.Path) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	knownPaths := make(map[string]struct{}, len(w.paths))
+	for _, path := range w.paths {
+		knownPaths[path.String()] = struct{}{}
+	}
+
+	for _, path := range paths {
+		if _, known := knownPaths[path.String()]; known {
+			continue
+		}
+
+		knownPaths[path.String()] = struct{}{}
+		w.paths = append(w.paths, path)
+	}
+}
+
+// Discover adds the used BIP44 paths of the coin type of the KeyManager to the wallet, see DiscoverPaths.
+// Call Sync afterward to fetch the outputs of the discovered addresses.
+func (w *Wallet) Discover(ctx context.Context, opts ...options.Option[DiscoveryOptions]) error {
+	paths, err := DiscoverPaths(ctx, w.keyManager, w.indexer, w.client.CommittedAPI().ProtocolParameters().Bech32HRP(), opts...)
+	if err != nil {
+		return err
+	}
+
+	w.AddPaths(paths...)
+
+	return nil
+}
+
+// AddressSigner returns an address signer for the addresses of the wallet.
+func (w *Wallet) AddressSigner() iotago.AddressSigner {
+	addressKeys := make([]iotago.AddressKeys, 0)
+	for _, path := range w.Paths() {
+		addressKeys = append(addressKeys, w.keyManager.ForPath(path).addressKeys()...)
+	}
+
+	return iotago.NewInMemoryAddressSigner(addressKeys...)
+}
+
+// Addresses returns the Ed25519 and implicit account creation addresses of all derivation paths of the wallet.
+func (w *Wallet) Addresses() []iotago.Address {
+	paths := w.Paths()
+
+	addresses := make([]iotago.Address, 0, 2*len(paths))
+	for _, path := range paths {
+		keyManager := w.keyManager.ForPath(path)
+		addresses = append(addresses,
+			keyManager.Address(iotago.AddressEd25519),
+			keyManager.Address(iotago.AddressImplicitAccountCreation),
+		)
+	}
+
+	return addresses
+}
+
+// SyncedSlot returns the committed slot of the last synchronization.
+func (w *Wallet) SyncedSlot() iotago.SlotIndex {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.syncedSlot
+}
+
+// Sync queries the indexer for the outputs unlockable by the addresses of the wallet and replaces the known outputs.
+// Locks of outputs which are not unspent anymore are released.
+func (w *Wallet) Sync(ctx context.Context) error {
+	outputs, syncedSlot, err := w.queryOutputs(ctx, w.Addresses())
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.outputs = outputs
+	w.syncedSlot = syncedSlot
+	w.releaseSpentLocks()
+
+	return nil
+}
+
+// syncAddresses queries the indexer for the outputs unlockable by the given addresses and only replaces the known outputs of those addresses.
+func (w *Wallet) syncAddresses(ctx context.Context, addresses []iotago.Address) error {
+	outputs, syncedSlot, err := w.queryOutputs(ctx, addresses)
+	if err != nil {
+		return err
+	}
+
+	syncedAddresses := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		syncedAddresses[address.Key()] = struct{}{}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for outputID, output := range w.outputs {
+		if _, synced := syncedAddresses[output.Address.Key()]; synced {
+			delete(w.outputs, outputID)
+		}
+	}
+	for outputID, output := range outputs {
+		w.outputs[outputID] = output
+	}
+
+	if syncedSlot > w.syncedSlot {
+		w.syncedSlot = syncedSlot
+	}
+	w.releaseSpentLocks()
+
+	return nil
+}
+
+// queryOutputs queries the indexer for the outputs unlockable by the given addresses
+// and returns them together with the highest committed slot the indexer answered with.
+func (w *Wallet) queryOutputs(ctx context.Context, addresses []iotago.Address) (map[iotago.OutputID]*OwnedOutput, iotago.SlotIndex, error) {
+	outputs := make(map[iotago.OutputID]*OwnedOutput)
+	var syncedSlot iotago.SlotIndex
+
+	for _, address := range addresses {
+		committedSlot, err := w.syncAddress(ctx, address, outputs)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if committedSlot > syncedSlot {
+			syncedSlot = committedSlot
+		}
+	}
+
+	return outputs, syncedSlot, nil
+}
+
+// releaseSpentLocks releases the locks of outputs which are not unspent anymore.
+// The mutex needs to be held by the caller.
+func (w *Wallet) releaseSpentLocks() {
+	for outputID := range w.lockedOutputs {
+		if _, exists := w.outputs[outputID]; !exists {
+			delete(w.lockedOutputs, outputID)
+		}
+	}
+}
+
+// syncAddress adds the outputs unlockable by the given address to the given map
+// and returns the committed slot the indexer answered with.
+func (w *Wallet) syncAddress(ctx context.Context, address iotago.Address, outputs map[iotago.OutputID]*OwnedOutput) (iotago.SlotIndex, error) {
+	query := &api.OutputsQuery{
+		IndexerUnlockableByAddressParams: api.IndexerUnlockableByAddressParams{
+			UnlockableByAddressBech32: address.Bech32(w.client.CommittedAPI().ProtocolParameters().Bech32HRP()),
+		},
+	}
+
+	resultSet, err := w.indexer.Outputs(ctx, query)
+	if err != nil {
+		return 0, ierrors.Wrapf(err, "failed to query the outputs of address %s", address)
+	}
+
+	var committedSlot iotago.SlotIndex
+	for resultSet.Next() {
+		outputIDs, err := resultSet.Response.Items.OutputIDs()
+		if err != nil {
+			return 0, ierrors.Wrap(err, "failed to parse the output IDs of the indexer response")
+		}
+
+		fetchedOutputs, err := resultSet.Outputs(ctx)
+		if err != nil {
+			return 0, ierrors.Wrapf(err, "failed to fetch the outputs of address %s", address)
+		}
+
+		for i, outputID := range outputIDs {
+			outputs[outputID] = &OwnedOutput{
+				OutputID: outputID,
+				Output:   fetchedOutputs[i],
+				Address:  address,
+			}
+		}
+
+		committedSlot = resultSet.Response.CommittedSlot
+	}
+
+	if resultSet.Error != nil {
+		return 0, ierrors.Wrapf(resultSet.Error, "failed to query the outputs of address %s", address)
+	}
+
+	return committedSlot, nil
+}
+
+// Listen keeps the wallet up to date by synchronizing an address every time one of its outputs
+// is created or spent. The given EventAPIClient needs to be connected.
+// Listen blocks until the context is done or the subscriptions are closed, errors during the
+// synchronization are passed to the handler set with WithSyncErrorHandler.
+func (w *Wallet) Listen(ctx context.Context, eventAPIClient *nodeclient.EventAPIClient) error {
+	netPrefix := w.client.CommittedAPI().ProtocolParameters().Bech32HRP()
+
+	// the events only contain the outputs without their IDs, so they only mark the address as changed.
+	// multiple events that arrive during a synchronization are coalesced.
+	var changedAddressesMutex sync.Mutex
+	changedAddresses := make(map[string]iotago.Address)
+	syncRequests := make(chan struct{}, 1)
+	addressChanged := func(address iotago.Address) {
+		changedAddressesMutex.Lock()
+		changedAddresses[address.Key()] = address
+		changedAddressesMutex.Unlock()
+
+		select {
+		case syncRequests <- struct{}{}:
+		default:
+		}
+	}
+
+	var subscriptions []*nodeclient.EventAPIClientSubscription
+	defer func() {
+		for _, subscription := range subscriptions {
+			_ = subscription.Close()
+		}
+	}()
+
+	var listeners sync.WaitGroup
+	for _, address := range w.Addresses() {
+		createdOutputs, createdSubscription := eventAPIClient.OutputsByUnlockConditionAndAddress(address, netPrefix, nodeclient.UnlockConditionAny)
+		if err := createdSubscription.Error(); err != nil {
+			return ierrors.Wrapf(err, "failed to subscribe to the created outputs of address %s", address)
+		}
+		subscriptions = append(subscriptions, createdSubscription)
+
+		spentOutputs, spentSubscription := eventAPIClient.SpentOutputsByUnlockConditionAndAddress(address, netPrefix, nodeclient.UnlockConditionAny)
+		if err := spentSubscription.Error(); err != nil {
+			return ierrors.Wrapf(err, "failed to subscribe to the spent outputs of address %s", address)
+		}
+		subscriptions = append(subscriptions, spentSubscription)
+
+		for _, outputs := range []<-chan iotago.Output{createdOutputs, spentOutputs} {
+			listeners.Add(1)
+			go func(address iotago.Address, outputs <-chan iotago.Output) {
+				defer listeners.Done()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case _, ok := <-outputs:
+						if !ok {
+							return
+						}
+						addressChanged(address)
+					}
+				}
+			}(address, outputs)
+		}
+	}
+
+	// stop listening once all subscriptions are closed.
+	listenersDone := make(chan struct{})
+	go func() {
+		listeners.Wait()
+		close(listenersDone)
+	}()
+
+	// outputs might have changed before the subscriptions were active.
+	if err := w.Sync(ctx); err != nil && ctx.Err() == nil {
+		w.opts.syncErrorHandler(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-listenersDone:
+			return nil
+		case <-syncRequests:
+			changedAddressesMutex.Lock()
+			addresses := make([]iotago.Address, 0, len(changedAddresses))
+			for key, address := range changedAddresses {
+				addresses = append(addresses, address)
+				delete(changedAddresses, key)
+			}
+			changedAddressesMutex.Unlock()
+
+			if err := w.syncAddresses(ctx, addresses); err != nil && ctx.Err() == nil {
+				w.opts.syncErrorHandler(err)
+			}
+		}
+	}
+}
+
+// Outputs returns the unspent outputs of the wallet, including the locked ones, sorted by their ID.
+func (w *Wallet) Outputs() []*OwnedOutput {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.sortedOutputs(func(*OwnedOutput) bool { return true })
+}
+
+// UnlockedOutputs returns the unspent outputs of the wallet which are not used in a pending transaction, sorted by their ID.
+func (w *Wallet) UnlockedOutputs() []*OwnedOutput {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.sortedOutputs(func(output *OwnedOutput) bool {
+		_, locked := w.lockedOutputs[output.OutputID]
+		return !locked
+	})
+}
+
+// Inputs returns the unlocked outputs of the wallet as inputs for the builder.TransactionBuilder,
+// e.g. as candidates for builder.TransactionBuilder.SelectInputs.
+func (w *Wallet) Inputs() []*builder.TxInput {
+	unlockedOutputs := w.UnlockedOutputs()
+
+	inputs := make([]*builder.TxInput, 0, len(unlockedOutputs))
+	for _, output := range unlockedOutputs {
+		inputs = append(inputs, &builder.TxInput{
+			UnlockTarget: output.Address,
+			InputID:      output.OutputID,
+			Input:        output.Output,
+		})
+	}
+
+	return inputs
+}
+
+// sortedOutputs returns the outputs which match the given filter sorted by their ID.
+// The caller needs to hold the lock.
+func (w *Wallet) sortedOutputs(filter func(output *OwnedOutput) bool) []*OwnedOutput {
+	outputs := make([]*OwnedOutput, 0, len(w.outputs))
+	for _, output := range w.outputs {
+		if filter(output) {
+			outputs = append(outputs, output)
+		}
+	}
+
+	sort.Slice(outputs, func(i, j int) bool {
+		return bytes.Compare(outputs[i].OutputID[:], outputs[j].OutputID[:]) < 0
+	})
+
+	return outputs
+}
+
+// LockOutputs locks the given outputs for the pending transaction with the given ID.
+// Locked outputs are excluded from the inputs and the available balance of the wallet
+// until they are unlocked again or disappear during a synchronization.
+// Either all or none of the outputs are locked.
+func (w *Wallet) LockOutputs(transactionID iotago.TransactionID, outputIDs ...iotago.OutputID) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, outputID := range outputIDs {
+		if _, exists := w.outputs[outputID]; !exists {
+			return ierrors.Wrapf(ErrOutputNotOwned, "output %s", outputID.ToHex())
+		}
+
+		if lockingTransactionID, locked := w.lockedOutputs[outputID]; locked && lockingTransactionID != transactionID {
+			return ierrors.Wrapf(ErrOutputLocked, "output %s is used in transaction %s", outputID.ToHex(), lockingTransactionID.ToHex())
+		}
+	}
+
+	for _, outputID := range outputIDs {
+		w.lockedOutputs[outputID] = transactionID
+	}
+
+	return nil
+}
+
+// LockInputs locks the inputs of the given transaction which are owned by the wallet.
+func (w *Wallet) LockInputs(transaction *iotago.Transaction) error {
+	transactionID, err := transaction.ID()
+	if err != nil {
+		return ierrors.Wrap(err, "failed to compute the transaction ID")
+	}
+
+	inputs, err := transaction.Inputs()
+	if err != nil {
+		return ierrors.Wrap(err, "failed to get the inputs of the transaction")
+	}
+
+	w.mutex.RLock()
+	ownedInputIDs := make([]iotago.OutputID, 0, len(inputs))
+	for _, input := range inputs {
+		if _, exists := w.outputs[input.OutputID()]; exists {
+			ownedInputIDs = append(ownedInputIDs, input.OutputID())
+		}
+	}
+	w.mutex.RUnlock()
+
+	return w.LockOutputs(transactionID, ownedInputIDs...)
+}
+
+// UnlockOutputs releases the outputs locked for the transaction with the given ID,
+// e.g. because the transaction failed.
+func (w *Wallet) UnlockOutputs(transactionID iotago.TransactionID) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for outputID, lockingTransactionID := range w.lockedOutputs {
+		if lockingTransactionID == transactionID {
+			delete(w.lockedOutputs, outputID)
+		}
+	}
+}
+
+// IsLocked returns whether the given output is used in a pending transaction.
+func (w *Wallet) IsLocked(outputID iotago.OutputID) bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	_, locked := w.lockedOutputs[outputID]
+
+	return locked
+}
+
+// Balance returns the balance of all unspent outputs of the wallet at the given slot.
+func (w *Wallet) Balance(targetSlot iotago.SlotIndex) (*Balance, error) {
+	return w.balance(targetSlot, w.Outputs())
+}
+
+// AvailableBalance returns the balance of the unspent outputs of the wallet at the given slot,
+// which are not used in a pending transaction.
+func (w *Wallet) AvailableBalance(targetSlot iotago.SlotIndex) (*Balance, error) {
+	return w.balance(targetSlot, w.UnlockedOutputs())
+}
+
+func (w *Wallet) balance(targetSlot iotago.SlotIndex, outputs []*OwnedOutput) (*Balance, error) {
+	apiForSlot := w.client.APIForSlot(targetSlot)
+
+	balance := &Balance{
+		NativeTokens: make(iotago.NativeTokenSum),
+		NFTs:         make([]iotago.NFTID, 0),
+		Accounts:     make([]iotago.AccountID, 0),
+	}
+
+	var err error
+	for _, ownedOutput := range outputs {
+		output := ownedOutput.Output
+
+		if balance.BaseTokens, err = safemath.SafeAdd(balance.BaseTokens, output.BaseTokenAmount()); err != nil {
+			return nil, ierrors.Wrap(err, "failed to sum the base tokens")
+		}
+
+		potentialMana, err := iotago.PotentialMana(apiForSlot.ManaDecayProvider(), apiForSlot.StorageScoreStructure(), output, ownedOutput.OutputID.CreationSlot(), targetSlot)
+		if err != nil {
+			return nil, ierrors.Wrapf(err, "failed to calculate the potential mana of output %s", ownedOutput.OutputID.ToHex())
+		}
+
+		storedMana, err := apiForSlot.ManaDecayProvider().DecayManaBySlots(output.StoredMana(), ownedOutput.OutputID.CreationSlot(), targetSlot)
+		if err != nil {
+			return nil, ierrors.Wrapf(err, "failed to calculate the stored mana decay of output %s", ownedOutput.OutputID.ToHex())
+		}
+
+		for _, mana := range []iotago.Mana{potentialMana, storedMana} {
+			if balance.Mana, err = safemath.SafeAdd(balance.Mana, mana); err != nil {
+				return nil, ierrors.Wrap(err, "failed to sum the mana")
+			}
+		}
+
+		if nativeToken := output.FeatureSet().NativeToken(); nativeToken != nil {
+			balance.NativeTokens[nativeToken.ID] = new(big.Int).Add(balance.NativeTokens.ValueOrBigInt0(nativeToken.ID), nativeToken.Amount)
+		}
+
+		switch output := output.(type) {
+		case *iotago.NFTOutput:
+			nftID := output.NFTID
+			if nftID.Empty() {
+				nftID = iotago.NFTIDFromOutputID(ownedOutput.OutputID)
+			}
+			balance.NFTs = append(balance.NFTs, nftID)
+		case *iotago.AccountOutput:
+			accountID := output.AccountID
+			if accountID.Empty() {
+				accountID = iotago.AccountIDFromOutputID(ownedOutput.OutputID)
+			}
+			balance.Accounts = append(balance.Accounts, accountID)
+		}
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,292 @@
+package wallet_test
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/lo"
+	iotago "github.com/iotaledger/iota.go/v4"
+	"github.com/iotaledger/iota.go/v4/api"
+	"github.com/iotaledger/iota.go/v4/nodeclient"
+	"github.com/iotaledger/iota.go/v4/tpkg"
+	"github.com/iotaledger/iota.go/v4/wallet"
+)
+
+var testAPI = iotago.V3API(tpkg.IOTAMainnetV3TestProtocolParameters)
+
+// standInNode is a minimal node which serves the routes used by the wallet from an in-memory ledger.
+type standInNode struct {
+	*httptest.Server
+
+	mutex         sync.Mutex
+	committedSlot iotago.SlotIndex
+	outputs       map[iotago.OutputID]*api.OutputResponse
+	blockIssuance *api.IssuanceBlockHeaderResponse
+	blocks        []*iotago.Block
+}
+
+func newStandInNode(t *testing.T) *standInNode {
+	t.Helper()
+
+	node := &standInNode{
+		outputs: make(map[iotago.OutputID]*api.OutputResponse),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.CoreRouteInfo, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, &api.InfoResponse{
+			Name:    "stand-in",
+			Version: "1.0.0",
+			Status:  &api.InfoResNodeStatus{IsHealthy: true},
+			ProtocolParameters: []*api.InfoResProtocolParameters{
+				{StartEpoch: 0, Parameters: tpkg.IOTAMainnetV3TestProtocolParameters},
+			},
+			BaseToken: &api.InfoResBaseToken{Name: "TestCoin", TickerSymbol: "TEST", Unit: "TEST", Decimals: 6},
+			Metrics:   &api.InfoResNodeMetrics{},
+		})
+	})
+	mux.HandleFunc(api.RouteRoutes, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, &api.RoutesResponse{Routes: []iotago.PrefixedStringUint8{api.CorePluginName, api.IndexerPluginName}})
+	})
+	mux.HandleFunc(api.IndexerRouteOutputs, func(w http.ResponseWriter, r *http.Request) {
+		_, address, err := iotago.ParseBech32(r.URL.Query().Get("unlockableByAddress"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		node.mutex.Lock()
+		items := make(iotago.HexOutputIDs, 0)
+		for outputID, response := range node.outputs {
+			//nolint:forcetypeassert // only basic outputs are used in the tests
+			if response.Output.(*iotago.BasicOutput).UnlockConditionSet().Address().Address.Equal(address) {
+				items = append(items, iotago.HexOutputID(outputID.ToHex()))
+			}
+		}
+		res := &api.IndexerResponse{CommittedSlot: node.committedSlot, PageSize: 1000, Items: items}
+		node.mutex.Unlock()
+
+		writeJSON(w, res)
+	})
+	mux.HandleFunc(strings.TrimSuffix(api.CoreRouteOutput, "{"+api.ParameterOutputID+"}"), func(w http.ResponseWriter, r *http.Request) {
+		outputID, err := iotago.OutputIDFromHexString(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		node.mutex.Lock()
+		response, exists := node.outputs[outputID]
+		node.mutex.Unlock()
+
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		responseBytes, err := testAPI.Encode(response)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", api.MIMEApplicationVendorIOTASerializerV2)
+		_, _ = w.Write(responseBytes)
+	})
+	mux.HandleFunc(api.CoreRouteBlockIssuance, func(w http.ResponseWriter, _ *http.Request) {
+		node.mutex.Lock()
+		res := node.blockIssuance
+		node.mutex.Unlock()
+
+		writeJSON(w, res)
+	})
+	mux.HandleFunc(api.CoreRouteBlocks, func(w http.ResponseWriter, r *http.Request) {
+		blockBytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		block, _, err := iotago.BlockFromBytes(iotago.SingleVersionProvider(testAPI))(blockBytes)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		blockID, err := block.ID()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		node.mutex.Lock()
+		node.blocks = append(node.blocks, block)
+		node.mutex.Unlock()
+
+		w.Header().Set("Location", blockID.ToHex())
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	node.Server = httptest.NewServer(mux)
+	t.Cleanup(node.Close)
+
+	return node
+}
+
+// writeJSON writes the given object as JSON response.
+// The handlers of the stand-in node don't fail the test themselves, as they don't run on the test goroutine.
+// Errors are answered with an internal server error instead, which fails the request of the wallet.
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	objBytes, err := testAPI.JSONEncode(obj)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", api.MIMEApplicationJSON)
+	_, _ = w.Write(objBytes)
+}
+
+// writeError answers the request with an internal server error.
+func writeError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// addOutputs creates a transaction with the given outputs in the given slot and adds them to the ledger.
+func (n *standInNode) addOutputs(t *testing.T, slot iotago.SlotIndex, outputs ...iotago.Output) iotago.OutputIDs {
+	t.Helper()
+
+	tx := &iotago.Transaction{
+		API: testAPI,
+		TransactionEssence: &iotago.TransactionEssence{
+			NetworkID:    testAPI.ProtocolParameters().NetworkID(),
+			CreationSlot: slot,
+			Inputs:       iotago.TxEssenceInputs{tpkg.RandUTXOInput()},
+		},
+		Outputs: lo.Map(outputs, func(output iotago.Output) iotago.TxEssenceOutput { return output }),
+	}
+
+	txID, err := tx.ID()
+	require.NoError(t, err)
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	outputIDs := make(iotago.OutputIDs, 0, len(outputs))
+	for i, output := range tx.Outputs {
+		proof, err := iotago.OutputIDProofFromTransaction(tx, uint16(i))
+		require.NoError(t, err)
+
+		outputID := iotago.OutputIDFromTransactionIDAndIndex(txID, uint16(i))
+		n.outputs[outputID] = &api.OutputResponse{Output: output, OutputIDProof: proof}
+		outputIDs = append(outputIDs, outputID)
+	}
+	n.committedSlot = slot
+
+	return outputIDs
+}
+
+// spendOutputs removes the given outputs from the ledger.
+func (n *standInNode) spendOutputs(outputIDs ...iotago.OutputID) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for _, outputID := range outputIDs {
+		delete(n.outputs, outputID)
+	}
+}
+
+func TestWallet(t *testing.T) {
+	node := newStandInNode(t)
+
+	client, err := nodeclient.New(node.URL)
+	require.NoError(t, err)
+
+	keyManager, err := wallet.NewKeyManagerFromRandom(wallet.DefaultIOTAPath)
+	require.NoError(t, err)
+
+	w, err := wallet.NewWallet(context.Background(), keyManager, client)
+	require.NoError(t, err)
+
+	walletAddr := keyManager.Address(iotago.AddressEd25519)
+	nativeTokenID := tpkg.RandNativeTokenID()
+
+	basicOutput := func(addr iotago.Address, amount iotago.BaseToken, mana iotago.Mana, features ...iotago.BasicOutputFeature) *iotago.BasicOutput {
+		return &iotago.BasicOutput{
+			Amount:           amount,
+			Mana:             mana,
+			UnlockConditions: iotago.BasicOutputUnlockConditions{&iotago.AddressUnlockCondition{Address: addr}},
+			Features:         features,
+		}
+	}
+
+	ownedOutputIDs := node.addOutputs(t, 10,
+		basicOutput(walletAddr, 1_000_000, 500),
+		basicOutput(walletAddr, 2_000_000, 0, &iotago.NativeTokenFeature{ID: nativeTokenID, Amount: big.NewInt(42)}),
+		basicOutput(tpkg.RandEd25519Address(), 5_000_000, 0),
+	)[:2]
+
+	require.NoError(t, w.Sync(context.Background()))
+	require.EqualValues(t, 10, w.SyncedSlot())
+	require.Len(t, w.Outputs(), 2)
+
+	t.Run("balance", func(t *testing.T) {
+		balance, err := w.Balance(10)
+		require.NoError(t, err)
+		require.EqualValues(t, 3_000_000, balance.BaseTokens)
+		require.EqualValues(t, 500, balance.Mana)
+		require.EqualValues(t, 42, balance.NativeTokens[nativeTokenID].Int64())
+
+		// mana is generated and decays over time
+		laterBalance, err := w.Balance(10 + testAPI.TimeProvider().EpochDurationSlots()*10)
+		require.NoError(t, err)
+		require.NotEqual(t, balance.Mana, laterBalance.Mana)
+	})
+
+	t.Run("locking", func(t *testing.T) {
+		txID := tpkg.RandTransactionID()
+		require.NoError(t, w.LockOutputs(txID, ownedOutputIDs[0]))
+		require.True(t, w.IsLocked(ownedOutputIDs[0]))
+
+		require.ErrorIs(t, w.LockOutputs(tpkg.RandTransactionID(), ownedOutputIDs...), wallet.ErrOutputLocked)
+		require.False(t, w.IsLocked(ownedOutputIDs[1]))
+		require.ErrorIs(t, w.LockOutputs(txID, tpkg.RandOutputID(0)), wallet.ErrOutputNotOwned)
+
+		inputs := w.Inputs()
+		require.Len(t, inputs, 1)
+		require.Equal(t, ownedOutputIDs[1], inputs[0].InputID)
+
+		available, err := w.AvailableBalance(10)
+		require.NoError(t, err)
+		require.EqualValues(t, 2_000_000, available.BaseTokens)
+
+		w.UnlockOutputs(txID)
+		require.False(t, w.IsLocked(ownedOutputIDs[0]))
+		require.Len(t, w.Inputs(), 2)
+	})
+
+	t.Run("sync releases locks of spent outputs", func(t *testing.T) {
+		require.NoError(t, w.LockOutputs(tpkg.RandTransactionID(), ownedOutputIDs[0]))
+
+		node.spendOutputs(ownedOutputIDs[0])
+		newOutputIDs := node.addOutputs(t, 20, basicOutput(walletAddr, 900_000, 0))
+
+		require.NoError(t, w.Sync(context.Background()))
+		require.EqualValues(t, 20, w.SyncedSlot())
+		require.False(t, w.IsLocked(ownedOutputIDs[0]))
+
+		expectedOutputIDs := iotago.OutputIDs{ownedOutputIDs[1], newOutputIDs[0]}
+		expectedOutputIDs.Sort()
+		require.Equal(t, expectedOutputIDs, iotago.OutputIDs(lo.Map(w.Outputs(), func(output *wallet.OwnedOutput) iotago.OutputID {
+			return output.OutputID
+		})))
+	})
+}